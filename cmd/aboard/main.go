@@ -1,31 +1,73 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/codywilliamson/aboard/internal/agent"
 	"github.com/codywilliamson/aboard/internal/config"
-	"github.com/codywilliamson/aboard/internal/trello"
+	"github.com/codywilliamson/aboard/internal/mcp"
 	"github.com/codywilliamson/aboard/internal/ui"
 )
 
+var version = "dev"
+
 func main() {
+	os.Exit(run())
+}
+
+// run returns the exit code, so deferred cleanup (closing the store,
+// deleting webhooks) happens before main exits.
+func run() int {
 	configPath := flag.String("config", "", "path to .env config file")
 	flag.StringVar(configPath, "c", "", "path to .env config file (shorthand)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: aboard [flags] [mcp]\n\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "  mcp    serve board tools over the model context protocol (stdio)\n\n")
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
 	cfg := config.Load(*configPath)
 
-	trelloClient := trello.NewClient(cfg.TrelloAPIKey, cfg.TrelloAPIToken)
-	runner := agent.NewRunner(cfg.CodexCommand, cfg.ClaudeCommand)
+	provider, closer, err := newProvider(cfg)
+	if err != nil {
+		log.Printf("provider: %v", err)
+		return 1
+	}
+	if closer != nil {
+		defer closer.Close()
+	}
+
+	switch flag.Arg(0) {
+	case "":
+	case "mcp":
+		// stdout carries the protocol, so nothing else may write to it
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		srv := mcp.NewServer(provider, cfg.BoardID, version)
+		if err := srv.Serve(ctx, os.Stdin, os.Stdout); err != nil && ctx.Err() == nil {
+			log.Printf("mcp: %v", err)
+			return 1
+		}
+		return 0
+	default:
+		flag.Usage()
+		return 2
+	}
+
+	runner := agent.NewRunner(cfg.Agents)
 
-	m := ui.NewModel(cfg, trelloClient, runner)
+	m := ui.NewModel(cfg, provider, runner)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		log.Printf("app error: %v", err)
-		os.Exit(1)
+		return 1
 	}
+	return 0
 }
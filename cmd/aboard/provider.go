@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/codywilliamson/aboard/internal/board"
+	"github.com/codywilliamson/aboard/internal/config"
+	"github.com/codywilliamson/aboard/internal/github"
+	"github.com/codywilliamson/aboard/internal/jira"
+	"github.com/codywilliamson/aboard/internal/local"
+	"github.com/codywilliamson/aboard/internal/markdown"
+	"github.com/codywilliamson/aboard/internal/trello"
+)
+
+// newProvider builds the board backend selected by ABOARD_PROVIDER.
+// the returned closer is nil when the provider holds no resources.
+func newProvider(cfg config.Config) (board.BoardProvider, io.Closer, error) {
+	switch cfg.Provider {
+	case "trello":
+		client := trello.NewClient(cfg.TrelloAPIKey, cfg.TrelloAPIToken)
+		if cfg.TrelloWebhookURL != "" {
+			client.EnableWebhook(cfg.TrelloWebhookAddr, cfg.TrelloWebhookURL, cfg.TrelloAPISecret)
+		}
+		// closing removes any webhook registered while the app ran
+		return client, client, nil
+	case "jira":
+		return jira.NewClient(cfg.JiraBaseURL, cfg.JiraEmail, cfg.JiraAPIToken, cfg.JiraIssueType), nil, nil
+	case "github":
+		return github.NewClient(cfg.GitHubEndpoint, cfg.GitHubToken, cfg.GitHubOwner, cfg.GitHubStatus), nil, nil
+	case "local":
+		store, err := local.Open(cfg.LocalDBPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store, nil
+	case "markdown":
+		dir, err := markdown.Open(cfg.MarkdownDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dir, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown provider %q (want trello, jira, github, local or markdown)", cfg.Provider)
+	}
+}
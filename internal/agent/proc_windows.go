@@ -0,0 +1,20 @@
+//go:build windows
+
+package agent
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// killTree makes cancellation take down the agent's whole process tree,
+// since agent CLIs commonly spawn their own helpers.
+func killTree(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		kill := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+		if err := kill.Run(); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+}
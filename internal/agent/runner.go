@@ -5,9 +5,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 type AgentName string
@@ -17,27 +19,89 @@ const (
 	AgentClaude AgentName = "claude"
 )
 
+const DefaultTimeout = 90 * time.Second
+
+// InputMode controls how the prompt reaches the agent process.
+type InputMode string
+
+const (
+	// InputAuto pipes the prompt to stdin unless the command uses {prompt} or {context}.
+	InputAuto InputMode = ""
+	// InputStdin always pipes the prompt to stdin.
+	InputStdin InputMode = "stdin"
+	// InputPlaceholder only passes the prompt through argument placeholders.
+	InputPlaceholder InputMode = "placeholder"
+)
+
+// Profile is a named agent configuration.
+type Profile struct {
+	Name    AgentName
+	Command []string
+	Timeout time.Duration
+	Dir     string
+	Env     []string // KEY=VALUE, added to the inherited environment
+	Input   InputMode
+}
+
 type Runner struct {
-	codexCmd  []string
-	claudeCmd []string
-	timeout   time.Duration
+	profiles []Profile
+}
+
+func NewRunner(profiles []Profile) *Runner {
+	r := &Runner{}
+	for _, p := range profiles {
+		p.Command = append([]string(nil), p.Command...)
+		p.Env = append([]string(nil), p.Env...)
+		if p.Timeout <= 0 {
+			p.Timeout = DefaultTimeout
+		}
+		r.profiles = append(r.profiles, p)
+	}
+	return r
 }
 
-func NewRunner(codexCmd, claudeCmd []string) *Runner {
-	return &Runner{
-		codexCmd:  append([]string(nil), codexCmd...),
-		claudeCmd: append([]string(nil), claudeCmd...),
-		timeout:   90 * time.Second,
+// Agents returns the configured profile names in config order.
+func (r *Runner) Agents() []AgentName {
+	names := make([]AgentName, 0, len(r.profiles))
+	for _, p := range r.profiles {
+		names = append(names, p.Name)
 	}
+	return names
 }
 
+// Event is one step of a streamed agent run. Text events carry stdout as it
+// arrives; the final event has Done set along with the full output or error.
+type Event struct {
+	Text   string
+	Done   bool
+	Output string
+	Err    error
+}
+
+// Ask runs the agent to completion and returns its output.
 func (r *Runner) Ask(ctx context.Context, agent AgentName, cardContext, userPrompt string) (string, error) {
+	events, err := r.Stream(ctx, agent, cardContext, userPrompt)
+	if err != nil {
+		return "", err
+	}
+	for ev := range events {
+		if ev.Done {
+			return ev.Output, ev.Err
+		}
+	}
+	return "", errors.New("agent stream ended without a result")
+}
+
+// Stream starts the agent and returns a channel of events. stdout is
+// forwarded as soon as it is read; the channel is closed after the Done event.
+func (r *Runner) Stream(ctx context.Context, agent AgentName, cardContext, userPrompt string) (<-chan Event, error) {
 	prompt := buildPrompt(cardContext, userPrompt)
 
-	cmdSpec, err := r.commandFor(agent)
+	profile, err := r.profile(agent)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	cmdSpec := profile.Command
 
 	args := make([]string, 0, len(cmdSpec)-1)
 	containsPromptPlaceholder := false
@@ -50,54 +114,126 @@ func (r *Runner) Ask(ctx context.Context, agent AgentName, cardContext, userProm
 		args = append(args, replaced)
 	}
 
-	runCtx, cancel := context.WithTimeout(ctx, r.timeout)
-	defer cancel()
+	runCtx, cancel := context.WithTimeout(ctx, profile.Timeout)
 
 	cmd := exec.CommandContext(runCtx, cmdSpec[0], args...)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
+	cmd.Dir = profile.Dir
+	if len(profile.Env) > 0 {
+		cmd.Env = append(os.Environ(), profile.Env...)
+	}
+	killTree(cmd)
+	// orphaned grandchildren can hold stdout open after a kill
+	cmd.WaitDelay = 2 * time.Second
+	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
+	stdoutPipe, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
 
-	if !containsPromptPlaceholder {
+	switch profile.Input {
+	case InputStdin:
 		cmd.Stdin = strings.NewReader(prompt)
+	case InputPlaceholder:
+	default:
+		if !containsPromptPlaceholder {
+			cmd.Stdin = strings.NewReader(prompt)
+		}
 	}
 
-	if err := cmd.Run(); err != nil {
-		errText := strings.TrimSpace(stderr.String())
-		if errText != "" {
-			return "", fmt.Errorf("agent command failed: %w: %s", err, errText)
-		}
-		return "", fmt.Errorf("agent command failed: %w", err)
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("agent command failed: %w", err)
 	}
 
-	out := strings.TrimSpace(stdout.String())
-	if out == "" {
-		if serr := strings.TrimSpace(stderr.String()); serr != "" {
-			out = serr
+	events := make(chan Event, 16)
+	go func() {
+		defer close(events)
+		defer cancel()
+
+		var stdout strings.Builder
+		var pending []byte
+		buf := make([]byte, 4096)
+		for {
+			n, rerr := stdoutPipe.Read(buf)
+			if n > 0 {
+				var chunk []byte
+				chunk, pending = splitRunes(append(pending, buf[:n]...))
+				if len(chunk) > 0 {
+					stdout.Write(chunk)
+					events <- Event{Text: string(chunk)}
+				}
+			}
+			if rerr != nil {
+				break
+			}
+		}
+		if len(pending) > 0 {
+			stdout.Write(pending)
+			events <- Event{Text: string(pending)}
 		}
-	}
-	if out == "" {
-		out = "(no output)"
-	}
 
-	return out, nil
+		if err := cmd.Wait(); err != nil {
+			switch {
+			case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+				err = fmt.Errorf("agent timed out after %s: %w", profile.Timeout, context.DeadlineExceeded)
+				events <- Event{Done: true, Output: strings.TrimSpace(stdout.String()), Err: err}
+				return
+			case errors.Is(ctx.Err(), context.Canceled):
+				events <- Event{Done: true, Output: strings.TrimSpace(stdout.String()), Err: context.Canceled}
+				return
+			}
+			errText := strings.TrimSpace(stderr.String())
+			if errText != "" {
+				err = fmt.Errorf("agent command failed: %w: %s", err, errText)
+			} else {
+				err = fmt.Errorf("agent command failed: %w", err)
+			}
+			events <- Event{Done: true, Output: strings.TrimSpace(stdout.String()), Err: err}
+			return
+		}
+
+		out := strings.TrimSpace(stdout.String())
+		if out == "" {
+			if serr := strings.TrimSpace(stderr.String()); serr != "" {
+				out = serr
+			}
+		}
+		if out == "" {
+			out = "(no output)"
+		}
+		events <- Event{Done: true, Output: out}
+	}()
+
+	return events, nil
 }
 
-func (r *Runner) commandFor(agent AgentName) ([]string, error) {
-	switch agent {
-	case AgentCodex:
-		if len(r.codexCmd) == 0 {
-			return nil, errors.New("codex command is not configured")
+// splitRunes holds back a trailing partial utf-8 sequence so chunks never
+// split a character.
+func splitRunes(b []byte) (complete, rest []byte) {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return b[:i], append([]byte(nil), b[i:]...)
+			}
+			break
 		}
-		return r.codexCmd, nil
-	case AgentClaude:
-		if len(r.claudeCmd) == 0 {
-			return nil, errors.New("claude command is not configured")
+	}
+	return b, nil
+}
+
+func (r *Runner) profile(agent AgentName) (Profile, error) {
+	for _, p := range r.profiles {
+		if p.Name != agent {
+			continue
 		}
-		return r.claudeCmd, nil
-	default:
-		return nil, fmt.Errorf("unknown agent: %s", agent)
+		if len(p.Command) == 0 {
+			return Profile{}, fmt.Errorf("%s command is not configured", agent)
+		}
+		return p, nil
 	}
+	return Profile{}, fmt.Errorf("unknown agent: %s", agent)
 }
 
 func buildPrompt(cardContext, userPrompt string) string {
@@ -112,6 +248,14 @@ func buildPrompt(cardContext, userPrompt string) string {
 		`  <action>{"type":"create_card","list_id":"...","name":"..."}</action>`,
 		`  <action>{"type":"create_list","name":"..."}</action>`,
 		`  <action>{"type":"archive_list","list_id":"..."}</action>`,
+		`  <action>{"type":"add_label","card_id":"...","label":"..."}</action>  (label id or name from the board's labels)`,
+		`  <action>{"type":"remove_label","card_id":"...","label":"..."}</action>`,
+		`  <action>{"type":"assign_member","card_id":"...","member":"..."}</action>  (member id, @username or name from the board's members; "me" is the user)`,
+		`  <action>{"type":"unassign_member","card_id":"...","member":"..."}</action>`,
+		`  <action>{"type":"set_due","card_id":"...","due":"..."}</action>  (e.g. "2025-03-14 17:00", "tomorrow 5pm", "+3d", "fri"; "none" clears)`,
+		`  <action>{"type":"add_checklist_item","card_id":"...","text":"...","checklist":"..."}</action>  (checklist id or name is optional: defaults to the card's first, created if missing)`,
+		`  <action>{"type":"check_item","card_id":"...","item":"...","checked":true}</action>  (item id or name from the card's checklists; "checked":false unchecks)`,
+		`  <action>{"type":"undo"}</action>  (reverts the last change listed in the context)`,
 		"",
 		"Board context:",
 		cardContext,
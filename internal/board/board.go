@@ -0,0 +1,227 @@
+package board
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUnsupported is wrapped by providers for operations their backend has no
+// equivalent for (e.g. creating jira board columns).
+var ErrUnsupported = errors.New("not supported by this provider")
+
+// BoardProvider is the backend a kanban board is read from and written to.
+// trello is the original implementation; the ui only talks to this interface.
+type BoardProvider interface {
+	Name() string
+	CanAuth() bool
+	Boards(ctx context.Context) ([]Board, error)
+	Lists(ctx context.Context, boardID string) ([]List, error)
+	Cards(ctx context.Context, boardID string) ([]Card, error)
+	MoveCard(ctx context.Context, cardID, listID string) error
+	UpdateCard(ctx context.Context, cardID string, fields CardUpdate) error
+	AddComment(ctx context.Context, cardID, text string) error
+	ArchiveCard(ctx context.Context, cardID string) error
+	CreateCard(ctx context.Context, listID, name string) (*Card, error)
+	CreateList(ctx context.Context, boardID, name string) (*List, error)
+	ArchiveList(ctx context.Context, listID string) error
+	// RestoreCard and RestoreList bring back an archived card or list.
+	RestoreCard(ctx context.Context, cardID string) error
+	RestoreList(ctx context.Context, listID string) error
+}
+
+type Board struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type List struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type Card struct {
+	ID       string
+	Name     string
+	Desc     string
+	URL      string
+	ShortURL string
+	IDList   string
+	ListName string
+	Labels   []Label
+	// MemberIDs are the board members assigned to the card.
+	MemberIDs []string
+	// Due and Start are nil when unset.
+	Due         *time.Time
+	Start       *time.Time
+	DueComplete bool
+	// CheckItems counts the items across the card's checklists,
+	// CheckItemsChecked how many of them are done.
+	CheckItems        int
+	CheckItemsChecked int
+}
+
+// Label is a board label. Color is the provider's color name (trello's
+// "green", "red_dark", ...) and may be empty, as may Name.
+type Label struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// Member is a person on a board.
+type Member struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	FullName string `json:"fullName"`
+}
+
+// Checklist is a named list of items on a card.
+type Checklist struct {
+	ID    string      `json:"id"`
+	Name  string      `json:"name"`
+	Items []CheckItem `json:"items"`
+}
+
+type CheckItem struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Checked bool   `json:"checked"`
+}
+
+// Activity is an entry in a card's history on the provider: a comment, or a
+// change someone made to the card.
+type Activity struct {
+	ID     string
+	Kind   string // "comment" or "update"
+	Author string
+	Text   string // the comment, or what changed
+	At     time.Time
+}
+
+// CardUpdate describes a partial card edit. nil fields are left unchanged.
+type CardUpdate struct {
+	Name *string
+	Desc *string
+}
+
+// Empty reports whether the update would change nothing.
+func (u CardUpdate) Empty() bool {
+	return u.Name == nil && u.Desc == nil
+}
+
+// AuthHelper is implemented by providers that can explain how to configure
+// their credentials when CanAuth reports false.
+type AuthHelper interface {
+	AuthHelp() []string
+}
+
+// Labeler is implemented by providers whose cards can carry board labels.
+type Labeler interface {
+	Labels(ctx context.Context, boardID string) ([]Label, error)
+	AddLabel(ctx context.Context, cardID, labelID string) error
+	RemoveLabel(ctx context.Context, cardID, labelID string) error
+}
+
+// Assigner is implemented by providers whose cards can be assigned to board
+// members.
+type Assigner interface {
+	Members(ctx context.Context, boardID string) ([]Member, error)
+	// Me is the member the provider is signed in as.
+	Me(ctx context.Context) (*Member, error)
+	AddMember(ctx context.Context, cardID, memberID string) error
+	RemoveMember(ctx context.Context, cardID, memberID string) error
+}
+
+// DueDater is implemented by providers whose cards have due dates.
+type DueDater interface {
+	// SetDue sets the card's due date; nil clears it.
+	SetDue(ctx context.Context, cardID string, due *time.Time) error
+	SetDueComplete(ctx context.Context, cardID string, complete bool) error
+}
+
+// Checklister is implemented by providers whose cards can have checklists.
+type Checklister interface {
+	Checklists(ctx context.Context, cardID string) ([]Checklist, error)
+	CreateChecklist(ctx context.Context, cardID, name string) (*Checklist, error)
+	AddCheckItem(ctx context.Context, checklistID, name string) (*CheckItem, error)
+	SetCheckItem(ctx context.Context, cardID, itemID string, checked bool) error
+	DeleteCheckItem(ctx context.Context, cardID, itemID string) error
+}
+
+// ActivityReader is implemented by providers that keep a card's comments and
+// change history.
+type ActivityReader interface {
+	// Activity returns the card's recent comments and changes, oldest first.
+	Activity(ctx context.Context, cardID string) ([]Activity, error)
+}
+
+// CommentDeleter is implemented by providers that can delete a comment
+// again, which lets adding one be undone.
+type CommentDeleter interface {
+	// PostComment adds a comment like AddComment and returns its id.
+	PostComment(ctx context.Context, cardID, text string) (string, error)
+	DeleteComment(ctx context.Context, cardID, commentID string) error
+}
+
+// Searcher is implemented by providers that can search across every board
+// the user can see.
+type Searcher interface {
+	// Search runs query through the provider's own search, so whatever
+	// modifiers it understands (trello's label:, @member, due:) work as typed.
+	Search(ctx context.Context, query string) (*SearchResults, error)
+}
+
+// SearchResults holds the boards and cards a search matched, best first.
+type SearchResults struct {
+	Boards []Board
+	Cards  []FoundCard
+}
+
+// FoundCard is a search hit along with the board it is on.
+type FoundCard struct {
+	Card
+	BoardID   string
+	BoardName string
+}
+
+// Syncer is implemented by providers that can report what changed on a board
+// since a cursor, so a refresh doesn't have to fetch every card again.
+type Syncer interface {
+	// Load fetches the board's lists and cards along with the cursor to sync
+	// from, in one go so nothing that happens during the load is missed.
+	Load(ctx context.Context, boardID string) (*Changes, error)
+	// Changes returns everything that changed after since. an empty since
+	// only fetches the current cursor.
+	Changes(ctx context.Context, boardID, since string) (*Changes, error)
+}
+
+// Changes is a delta against a previously loaded board.
+type Changes struct {
+	Cursor string
+	// Lists, when non-nil, replaces the board's lists.
+	Lists []List
+	// Cards holds the current state of created or changed cards.
+	Cards []Card
+	// Removed lists cards that were archived, deleted or left the board.
+	Removed []string
+	// Reload asks for a full reload: too much changed to patch.
+	Reload bool
+}
+
+// Watcher is implemented by providers that can push board changes as they
+// happen instead of being polled.
+type Watcher interface {
+	// Watch sends an Event for each change to boardID until ctx is cancelled,
+	// then closes the channel. providers that support watching only when
+	// configured return an error wrapping ErrUnsupported otherwise.
+	Watch(ctx context.Context, boardID string) (<-chan Event, error)
+}
+
+// Event is a change pushed by a Watcher. it only says that something changed;
+// the board is synced to find out what.
+type Event struct {
+	BoardID string
+	Type    string // provider action type, e.g. trello's "updateCard"
+	CardID  string
+}
@@ -5,30 +5,112 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/codywilliamson/aboard/internal/agent"
 )
 
 type Config struct {
+	Provider       string
+	BoardID        string
 	TrelloAPIKey   string
 	TrelloAPIToken string
 	TrelloBoardID  string
-	CodexCommand   []string
-	ClaudeCommand  []string
+	LocalDBPath    string
+	MarkdownDir    string
+	JiraBaseURL    string
+	JiraEmail      string
+	JiraAPIToken   string
+	JiraIssueType  string
+	GitHubToken    string
+	GitHubOwner    string
+	GitHubStatus   string
+	GitHubEndpoint string
+	ActionApproval string
+	PollInterval   time.Duration
+	Agents         []agent.Profile
+
+	// trello webhook receiver: public callback url, local listen address and
+	// the app secret trello signs callbacks with
+	TrelloWebhookURL  string
+	TrelloWebhookAddr string
+	TrelloAPISecret   string
 }
 
+// action approval policies: which agent actions wait in the review queue
+// before they run.
+const (
+	ApproveAuto        = "auto"
+	ApproveDestructive = "destructive"
+	ApproveAll         = "all"
+)
+
 // ConfigPath returns the path of the .env file that was loaded, or empty if none found.
 var ConfigPath string
 
 func Load(explicit string) Config {
 	ConfigPath = loadConfig(explicit)
 
-	return Config{
+	cfg := Config{
+		Provider:       strings.ToLower(strings.TrimSpace(os.Getenv("ABOARD_PROVIDER"))),
+		BoardID:        os.Getenv("ABOARD_BOARD_ID"),
 		TrelloAPIKey:   os.Getenv("TRELLO_API_KEY"),
 		TrelloAPIToken: os.Getenv("TRELLO_API_TOKEN"),
 		TrelloBoardID:  os.Getenv("TRELLO_BOARD_ID"),
-		CodexCommand:   commandFromEnv("TRELLO_TUI_CODEX_COMMAND", []string{"codex"}),
-		ClaudeCommand:  commandFromEnv("TRELLO_TUI_CLAUDE_COMMAND", []string{"claude"}),
+		LocalDBPath:    os.Getenv("ABOARD_LOCAL_DB"),
+		MarkdownDir:    os.Getenv("ABOARD_MARKDOWN_DIR"),
+		JiraBaseURL:    os.Getenv("JIRA_BASE_URL"),
+		JiraEmail:      os.Getenv("JIRA_EMAIL"),
+		JiraAPIToken:   os.Getenv("JIRA_API_TOKEN"),
+		JiraIssueType:  os.Getenv("JIRA_ISSUE_TYPE"),
+		GitHubToken:    firstEnv("GITHUB_TOKEN", "GH_TOKEN"),
+		GitHubOwner:    os.Getenv("GITHUB_OWNER"),
+		GitHubStatus:   os.Getenv("GITHUB_STATUS_FIELD"),
+		GitHubEndpoint: os.Getenv("GITHUB_GRAPHQL_URL"),
+		ActionApproval: approvalFromEnv(),
+		PollInterval:   pollFromEnv(),
+		Agents:         agentsFromEnv(),
+
+		TrelloWebhookURL:  os.Getenv("TRELLO_WEBHOOK_URL"),
+		TrelloWebhookAddr: os.Getenv("TRELLO_WEBHOOK_ADDR"),
+		TrelloAPISecret:   os.Getenv("TRELLO_API_SECRET"),
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = "trello"
+	}
+	// TRELLO_BOARD_ID predates ABOARD_BOARD_ID and only makes sense for trello
+	if cfg.BoardID == "" && cfg.Provider == "trello" {
+		cfg.BoardID = cfg.TrelloBoardID
+	}
+	return cfg
+}
+
+// approvalFromEnv reads ABOARD_ACTION_APPROVAL. anything unrecognised falls
+// back to confirming destructive actions rather than running them unchecked.
+func approvalFromEnv() string {
+	switch v := strings.ToLower(strings.TrimSpace(os.Getenv("ABOARD_ACTION_APPROVAL"))); v {
+	case ApproveAuto, ApproveAll:
+		return v
+	default:
+		return ApproveDestructive
+	}
+}
+
+// minPollInterval keeps background polling from eating the provider's rate
+// limit.
+const minPollInterval = 5 * time.Second
+
+// pollFromEnv reads ABOARD_POLL_INTERVAL (duration or seconds). unset or zero
+// turns background polling off.
+func pollFromEnv() time.Duration {
+	d := durationFromEnv("ABOARD_POLL_INTERVAL")
+	if d <= 0 {
+		return 0
 	}
+	return max(d, minPollInterval)
 }
 
 // loadConfig tries to load a .env file from the first location that exists.
@@ -64,6 +146,114 @@ func loadConfig(explicit string) string {
 	return ""
 }
 
+// agentsFromEnv reads the profiles named in ABOARD_AGENTS (comma separated,
+// in cycle order). each profile is configured with ABOARD_AGENT_<NAME>_*:
+//
+//	COMMAND  json array, e.g. ["claude","-p","{prompt}"]
+//	TIMEOUT  duration ("2m") or seconds
+//	DIR      working directory
+//	ENV      json object of extra environment variables
+//	INPUT    stdin | placeholder (default: stdin unless a placeholder is used)
+//
+// without ABOARD_AGENTS the codex and claude profiles are used, still
+// honouring the older TRELLO_TUI_*_COMMAND variables.
+func agentsFromEnv() []agent.Profile {
+	legacy := map[string]struct {
+		env      string
+		fallback []string
+	}{
+		"codex":  {"TRELLO_TUI_CODEX_COMMAND", []string{"codex"}},
+		"claude": {"TRELLO_TUI_CLAUDE_COMMAND", []string{"claude"}},
+	}
+
+	names := []string{"codex", "claude"}
+	if raw := strings.TrimSpace(os.Getenv("ABOARD_AGENTS")); raw != "" {
+		names = nil
+		for _, name := range strings.Split(raw, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var profiles []agent.Profile
+	for _, name := range names {
+		prefix := "ABOARD_AGENT_" + envKey(name) + "_"
+		var fallback []string
+		if l, ok := legacy[strings.ToLower(name)]; ok {
+			fallback = commandFromEnv(l.env, l.fallback)
+		}
+		profiles = append(profiles, agent.Profile{
+			Name:    agent.AgentName(name),
+			Command: commandFromEnv(prefix+"COMMAND", fallback),
+			Timeout: durationFromEnv(prefix + "TIMEOUT"),
+			Dir:     os.Getenv(prefix + "DIR"),
+			Env:     envFromJSON(prefix + "ENV"),
+			Input:   agent.InputMode(strings.ToLower(os.Getenv(prefix + "INPUT"))),
+		})
+	}
+	return profiles
+}
+
+// envKey upper-cases a profile name and replaces anything that can't appear
+// in an env var name: "my-agent" -> "MY_AGENT".
+func envKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func durationFromEnv(name string) time.Duration {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(raw); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+func envFromJSON(name string) []string {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return nil
+	}
+	var vars map[string]string
+	if err := json.Unmarshal([]byte(raw), &vars); err != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	env := make([]string, 0, len(keys))
+	for _, k := range keys {
+		env = append(env, k+"="+vars[k])
+	}
+	return env
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 func commandFromEnv(name string, fallback []string) []string {
 	raw := os.Getenv(name)
 	if raw == "" {
@@ -0,0 +1,510 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+const defaultEndpoint = "https://api.github.com/graphql"
+
+// Client maps github projects (v2) onto the kanban model: projects are
+// boards, options of the single-select status field are lists and project
+// items (issues, pull requests and draft issues) are cards.
+type Client struct {
+	endpoint    string
+	token       string
+	owner       string
+	statusField string
+	http        *http.Client
+}
+
+var _ board.BoardProvider = (*Client)(nil)
+
+// NewClient lists the viewer's projects, or owner's when set (a user or org
+// login). endpoint defaults to api.github.com and statusField to "Status".
+func NewClient(endpoint, token, owner, statusField string) *Client {
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+	if statusField == "" {
+		statusField = "Status"
+	}
+	return &Client{
+		endpoint:    endpoint,
+		token:       token,
+		owner:       owner,
+		statusField: statusField,
+		http: &http.Client{
+			Timeout: 12 * time.Second,
+		},
+	}
+}
+
+func (c *Client) Name() string {
+	return "github"
+}
+
+func (c *Client) CanAuth() bool {
+	return c.token != ""
+}
+
+func (c *Client) AuthHelp() []string {
+	return []string{
+		"set these env vars:",
+		"  GITHUB_TOKEN        (needs the project scope)",
+		"  GITHUB_OWNER        (optional, user or org whose projects to list)",
+		"  GITHUB_STATUS_FIELD (optional, default Status)",
+	}
+}
+
+type projectNodes struct {
+	Nodes []struct {
+		ID     string `json:"id"`
+		Title  string `json:"title"`
+		Closed bool   `json:"closed"`
+	} `json:"nodes"`
+}
+
+const viewerProjectsQuery = `query {
+  viewer { projectsV2(first: 100) { nodes { id title closed } } }
+}`
+
+const ownerProjectsQuery = `query($login: String!) {
+  repositoryOwner(login: $login) {
+    ... on ProjectV2Owner { projectsV2(first: 100) { nodes { id title closed } } }
+  }
+}`
+
+func (c *Client) Boards(ctx context.Context) ([]board.Board, error) {
+	if !c.CanAuth() {
+		return nil, errors.New("missing GITHUB_TOKEN")
+	}
+
+	var projects projectNodes
+	if c.owner == "" {
+		var data struct {
+			Viewer struct {
+				ProjectsV2 projectNodes `json:"projectsV2"`
+			} `json:"viewer"`
+		}
+		if err := c.query(ctx, viewerProjectsQuery, nil, &data); err != nil {
+			return nil, err
+		}
+		projects = data.Viewer.ProjectsV2
+	} else {
+		var data struct {
+			RepositoryOwner *struct {
+				ProjectsV2 projectNodes `json:"projectsV2"`
+			} `json:"repositoryOwner"`
+		}
+		if err := c.query(ctx, ownerProjectsQuery, map[string]any{"login": c.owner}, &data); err != nil {
+			return nil, err
+		}
+		if data.RepositoryOwner == nil {
+			return nil, fmt.Errorf("github owner %q not found", c.owner)
+		}
+		projects = data.RepositoryOwner.ProjectsV2
+	}
+
+	boards := make([]board.Board, 0, len(projects.Nodes))
+	for _, p := range projects.Nodes {
+		if p.Closed {
+			continue
+		}
+		boards = append(boards, board.Board{ID: p.ID, Name: p.Title})
+	}
+	return boards, nil
+}
+
+type singleSelectField struct {
+	ID      string `json:"id"`
+	Options []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"options"`
+}
+
+const statusFieldQuery = `query($id: ID!, $field: String!) {
+  node(id: $id) {
+    ... on ProjectV2 {
+      field(name: $field) { ... on ProjectV2SingleSelectField { id options { id name } } }
+    }
+  }
+}`
+
+func (c *Client) Lists(ctx context.Context, boardID string) ([]board.List, error) {
+	field, err := c.fetchStatusField(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	// items with no status get their own column, like the github board view
+	lists := []board.List{{ID: listID(boardID, field.ID, ""), Name: "No " + c.statusField}}
+	for _, opt := range field.Options {
+		lists = append(lists, board.List{ID: listID(boardID, field.ID, opt.ID), Name: opt.Name})
+	}
+	return lists, nil
+}
+
+type itemContent struct {
+	Typename string `json:"__typename"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	URL      string `json:"url"`
+	Number   int    `json:"number"`
+	Repo     struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+}
+
+const itemsQuery = `query($id: ID!, $field: String!, $after: String) {
+  node(id: $id) {
+    ... on ProjectV2 {
+      items(first: 100, after: $after) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          id
+          isArchived
+          fieldValueByName(name: $field) { ... on ProjectV2ItemFieldSingleSelectValue { optionId } }
+          content {
+            __typename
+            ... on DraftIssue { id title body }
+            ... on Issue { id title body url number repository { nameWithOwner } }
+            ... on PullRequest { id title body url number repository { nameWithOwner } }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func (c *Client) Cards(ctx context.Context, boardID string) ([]board.Card, error) {
+	if boardID == "" {
+		return nil, errors.New("board id is required")
+	}
+	lists, err := c.Lists(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	_, field, _, _ := splitListID(lists[0].ID)
+	nameByID := make(map[string]string, len(lists))
+	for _, l := range lists {
+		nameByID[l.ID] = l.Name
+	}
+
+	var cards []board.Card
+	var after *string
+	for {
+		var data struct {
+			Node struct {
+				Items struct {
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+					Nodes []struct {
+						ID         string `json:"id"`
+						IsArchived bool   `json:"isArchived"`
+						Status     *struct {
+							OptionID string `json:"optionId"`
+						} `json:"fieldValueByName"`
+						Content *itemContent `json:"content"`
+					} `json:"nodes"`
+				} `json:"items"`
+			} `json:"node"`
+		}
+		vars := map[string]any{"id": boardID, "field": c.statusField, "after": after}
+		if err := c.query(ctx, itemsQuery, vars, &data); err != nil {
+			return nil, err
+		}
+
+		for _, it := range data.Node.Items.Nodes {
+			if it.IsArchived || it.Content == nil {
+				continue
+			}
+			optionID := ""
+			if it.Status != nil {
+				optionID = it.Status.OptionID
+			}
+			lid := listID(boardID, field, optionID)
+			if _, ok := nameByID[lid]; !ok {
+				// option was deleted after the value was set
+				lid = lists[0].ID
+			}
+			short := it.Content.URL
+			if it.Content.Number > 0 {
+				short = fmt.Sprintf("%s#%d", it.Content.Repo.NameWithOwner, it.Content.Number)
+			}
+			cards = append(cards, board.Card{
+				ID:       it.ID,
+				Name:     it.Content.Title,
+				Desc:     it.Content.Body,
+				URL:      it.Content.URL,
+				ShortURL: short,
+				IDList:   lid,
+				ListName: nameByID[lid],
+			})
+		}
+
+		page := data.Node.Items.PageInfo
+		if !page.HasNextPage {
+			break
+		}
+		cursor := page.EndCursor
+		after = &cursor
+	}
+	return cards, nil
+}
+
+const setStatusMutation = `mutation($project: ID!, $item: ID!, $field: ID!, $option: String!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {singleSelectOptionId: $option}}) {
+    projectV2Item { id }
+  }
+}`
+
+const clearStatusMutation = `mutation($project: ID!, $item: ID!, $field: ID!) {
+  clearProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field}) {
+    projectV2Item { id }
+  }
+}`
+
+func (c *Client) MoveCard(ctx context.Context, cardID, listID string) error {
+	projectID, fieldID, optionID, err := splitListID(listID)
+	if err != nil {
+		return err
+	}
+	vars := map[string]any{"project": projectID, "item": cardID, "field": fieldID}
+	if optionID == "" {
+		return c.query(ctx, clearStatusMutation, vars, nil)
+	}
+	vars["option"] = optionID
+	return c.query(ctx, setStatusMutation, vars, nil)
+}
+
+const itemQuery = `query($id: ID!) {
+  node(id: $id) {
+    ... on ProjectV2Item {
+      project { id }
+      content {
+        __typename
+        ... on DraftIssue { id }
+        ... on Issue { id }
+        ... on PullRequest { id }
+      }
+    }
+  }
+}`
+
+type item struct {
+	Project struct {
+		ID string `json:"id"`
+	} `json:"project"`
+	Content *itemContent `json:"content"`
+}
+
+func (c *Client) item(ctx context.Context, itemID string) (*item, error) {
+	var data struct {
+		Node *item `json:"node"`
+	}
+	if err := c.query(ctx, itemQuery, map[string]any{"id": itemID}, &data); err != nil {
+		return nil, err
+	}
+	if data.Node == nil || data.Node.Content == nil {
+		return nil, fmt.Errorf("project item %s not found", itemID)
+	}
+	return data.Node, nil
+}
+
+func (c *Client) UpdateCard(ctx context.Context, cardID string, fields board.CardUpdate) error {
+	if fields.Empty() {
+		return nil
+	}
+	it, err := c.item(ctx, cardID)
+	if err != nil {
+		return err
+	}
+
+	input := map[string]any{}
+	if fields.Name != nil {
+		input["title"] = *fields.Name
+	}
+	if fields.Desc != nil {
+		input["body"] = *fields.Desc
+	}
+
+	var mutation string
+	switch it.Content.Typename {
+	case "DraftIssue":
+		input["draftIssueId"] = it.Content.ID
+		mutation = `mutation($input: UpdateProjectV2DraftIssueInput!) { updateProjectV2DraftIssue(input: $input) { draftIssue { id } } }`
+	case "Issue":
+		input["id"] = it.Content.ID
+		mutation = `mutation($input: UpdateIssueInput!) { updateIssue(input: $input) { issue { id } } }`
+	case "PullRequest":
+		input["pullRequestId"] = it.Content.ID
+		mutation = `mutation($input: UpdatePullRequestInput!) { updatePullRequest(input: $input) { pullRequest { id } } }`
+	default:
+		return fmt.Errorf("cannot edit %s items: %w", it.Content.Typename, board.ErrUnsupported)
+	}
+	return c.query(ctx, mutation, map[string]any{"input": input}, nil)
+}
+
+func (c *Client) AddComment(ctx context.Context, cardID, text string) error {
+	it, err := c.item(ctx, cardID)
+	if err != nil {
+		return err
+	}
+	if it.Content.Typename == "DraftIssue" {
+		return fmt.Errorf("draft issues have no comments: %w", board.ErrUnsupported)
+	}
+	mutation := `mutation($subject: ID!, $body: String!) { addComment(input: {subjectId: $subject, body: $body}) { subject { id } } }`
+	return c.query(ctx, mutation, map[string]any{"subject": it.Content.ID, "body": text}, nil)
+}
+
+// ArchiveCard archives the project item; the underlying issue is untouched.
+func (c *Client) ArchiveCard(ctx context.Context, cardID string) error {
+	it, err := c.item(ctx, cardID)
+	if err != nil {
+		return err
+	}
+	mutation := `mutation($project: ID!, $item: ID!) { archiveProjectV2Item(input: {projectId: $project, itemId: $item}) { item { id } } }`
+	return c.query(ctx, mutation, map[string]any{"project": it.Project.ID, "item": cardID}, nil)
+}
+
+// CreateCard adds a draft issue to the project and sets its status.
+func (c *Client) CreateCard(ctx context.Context, listID, name string) (*board.Card, error) {
+	projectID, _, optionID, err := splitListID(listID)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		AddProjectV2DraftIssue struct {
+			ProjectItem struct {
+				ID string `json:"id"`
+			} `json:"projectItem"`
+		} `json:"addProjectV2DraftIssue"`
+	}
+	mutation := `mutation($project: ID!, $title: String!) { addProjectV2DraftIssue(input: {projectId: $project, title: $title}) { projectItem { id } } }`
+	if err := c.query(ctx, mutation, map[string]any{"project": projectID, "title": name}, &data); err != nil {
+		return nil, err
+	}
+
+	card := &board.Card{ID: data.AddProjectV2DraftIssue.ProjectItem.ID, Name: name, IDList: listID}
+	if optionID != "" {
+		if err := c.MoveCard(ctx, card.ID, listID); err != nil {
+			return card, fmt.Errorf("created draft but could not set %s: %w", c.statusField, err)
+		}
+	}
+	return card, nil
+}
+
+func (c *Client) CreateList(ctx context.Context, boardID, name string) (*board.List, error) {
+	return nil, fmt.Errorf("edit %s field options in project settings: %w", c.statusField, board.ErrUnsupported)
+}
+
+func (c *Client) ArchiveList(ctx context.Context, listID string) error {
+	return fmt.Errorf("edit %s field options in project settings: %w", c.statusField, board.ErrUnsupported)
+}
+
+func (c *Client) RestoreCard(ctx context.Context, cardID string) error {
+	it, err := c.item(ctx, cardID)
+	if err != nil {
+		return err
+	}
+	mutation := `mutation($project: ID!, $item: ID!) { unarchiveProjectV2Item(input: {projectId: $project, itemId: $item}) { item { id } } }`
+	return c.query(ctx, mutation, map[string]any{"project": it.Project.ID, "item": cardID}, nil)
+}
+
+func (c *Client) RestoreList(ctx context.Context, listID string) error {
+	return fmt.Errorf("edit %s field options in project settings: %w", c.statusField, board.ErrUnsupported)
+}
+
+func (c *Client) fetchStatusField(ctx context.Context, projectID string) (*singleSelectField, error) {
+	if !c.CanAuth() {
+		return nil, errors.New("missing GITHUB_TOKEN")
+	}
+	var data struct {
+		Node *struct {
+			Field *singleSelectField `json:"field"`
+		} `json:"node"`
+	}
+	vars := map[string]any{"id": projectID, "field": c.statusField}
+	if err := c.query(ctx, statusFieldQuery, vars, &data); err != nil {
+		return nil, err
+	}
+	if data.Node == nil {
+		return nil, fmt.Errorf("project %s not found", projectID)
+	}
+	if data.Node.Field == nil || data.Node.Field.ID == "" {
+		return nil, fmt.Errorf("project has no single-select %q field", c.statusField)
+	}
+	return data.Node.Field, nil
+}
+
+// list ids are "<project id>/<field id>/<option id>" so a move carries
+// everything the mutation needs. an empty option is the "no status" column.
+func listID(projectID, fieldID, optionID string) string {
+	return projectID + "/" + fieldID + "/" + optionID
+}
+
+func splitListID(id string) (project, field, option string, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("invalid github list id %q", id)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+func (c *Client) query(ctx context.Context, query string, vars map[string]any, target any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("github returned %s", resp.Status)
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return err
+	}
+	if len(envelope.Errors) > 0 {
+		msgs := make([]string, 0, len(envelope.Errors))
+		for _, e := range envelope.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return fmt.Errorf("github: %s", strings.Join(msgs, "; "))
+	}
+	if target == nil || len(envelope.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(envelope.Data, target)
+}
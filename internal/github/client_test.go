@@ -0,0 +1,253 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+type gqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// fakeGitHub serves graphql requests with respond, recording each one.
+func fakeGitHub(t *testing.T, respond func(req gqlRequest) string) (*Client, *[]gqlRequest) {
+	t.Helper()
+	var reqs []gqlRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("authorization header = %q", got)
+		}
+		var req gqlRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		reqs = append(reqs, req)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(respond(req)))
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL, "tok", "", ""), &reqs
+}
+
+const statusFieldResponse = `{"data":{"node":{"field":{"id":"F1","options":[
+	{"id":"o1","name":"Todo"},{"id":"o2","name":"Done"}]}}}}`
+
+func TestBoardsSkipsClosedProjects(t *testing.T) {
+	c, _ := fakeGitHub(t, func(req gqlRequest) string {
+		return `{"data":{"viewer":{"projectsV2":{"nodes":[
+			{"id":"P1","title":"Roadmap","closed":false},
+			{"id":"P2","title":"Old","closed":true}]}}}}`
+	})
+
+	boards, err := c.Boards(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(boards) != 1 || boards[0] != (board.Board{ID: "P1", Name: "Roadmap"}) {
+		t.Fatalf("boards = %+v", boards)
+	}
+}
+
+func TestBoardsUnknownOwner(t *testing.T) {
+	c, reqs := fakeGitHub(t, func(req gqlRequest) string {
+		return `{"data":{"repositoryOwner":null}}`
+	})
+	c.owner = "nobody"
+
+	if _, err := c.Boards(context.Background()); err == nil || !strings.Contains(err.Error(), "nobody") {
+		t.Fatalf("err = %v", err)
+	}
+	if got := (*reqs)[0].Variables["login"]; got != "nobody" {
+		t.Fatalf("login = %v", got)
+	}
+}
+
+func TestListsStartWithNoStatus(t *testing.T) {
+	c, _ := fakeGitHub(t, func(req gqlRequest) string { return statusFieldResponse })
+
+	lists, err := c.Lists(context.Background(), "P1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []board.List{
+		{ID: "P1/F1/", Name: "No Status"},
+		{ID: "P1/F1/o1", Name: "Todo"},
+		{ID: "P1/F1/o2", Name: "Done"},
+	}
+	if len(lists) != len(want) {
+		t.Fatalf("lists = %+v", lists)
+	}
+	for i := range want {
+		if lists[i] != want[i] {
+			t.Errorf("lists[%d] = %+v, want %+v", i, lists[i], want[i])
+		}
+	}
+}
+
+func TestListsWithoutStatusField(t *testing.T) {
+	c, _ := fakeGitHub(t, func(req gqlRequest) string { return `{"data":{"node":{"field":null}}}` })
+
+	if _, err := c.Lists(context.Background(), "P1"); err == nil {
+		t.Fatal("expected an error for a project without the status field")
+	}
+}
+
+func TestCardsFollowsPages(t *testing.T) {
+	c, reqs := fakeGitHub(t, func(req gqlRequest) string {
+		if !strings.Contains(req.Query, "items(") {
+			return statusFieldResponse
+		}
+		if req.Variables["after"] == nil {
+			return `{"data":{"node":{"items":{
+				"pageInfo":{"hasNextPage":true,"endCursor":"c1"},
+				"nodes":[
+					{"id":"I1","fieldValueByName":{"optionId":"o1"},"content":{"__typename":"Issue","id":"X1","title":"Fix login","url":"https://github.com/o/r/issues/7","number":7,"repository":{"nameWithOwner":"o/r"}}},
+					{"id":"I2","isArchived":true,"content":{"__typename":"DraftIssue","id":"D0","title":"archived"}}
+				]}}}}`
+		}
+		return `{"data":{"node":{"items":{
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"},
+			"nodes":[
+				{"id":"I3","fieldValueByName":null,"content":{"__typename":"DraftIssue","id":"D1","title":"Idea","body":"notes"}},
+				{"id":"I4","fieldValueByName":{"optionId":"gone"},"content":{"__typename":"DraftIssue","id":"D2","title":"Orphan"}},
+				{"id":"I5","content":null}
+			]}}}}`
+	})
+
+	cards, err := c.Cards(context.Background(), "P1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 3 {
+		t.Fatalf("cards = %+v", cards)
+	}
+	if got := cards[0]; got.ID != "I1" || got.IDList != "P1/F1/o1" || got.ListName != "Todo" || got.ShortURL != "o/r#7" {
+		t.Errorf("issue card = %+v", got)
+	}
+	if got := cards[1]; got.ID != "I3" || got.IDList != "P1/F1/" || got.ListName != "No Status" || got.Desc != "notes" {
+		t.Errorf("draft card = %+v", got)
+	}
+	if got := cards[2]; got.ID != "I4" || got.IDList != "P1/F1/" {
+		t.Errorf("card with a deleted option = %+v", got)
+	}
+
+	var pages []any
+	for _, r := range *reqs {
+		if strings.Contains(r.Query, "items(") {
+			pages = append(pages, r.Variables["after"])
+		}
+	}
+	if len(pages) != 2 || pages[0] != nil || pages[1] != "c1" {
+		t.Fatalf("item page cursors = %v", pages)
+	}
+}
+
+func TestMoveCardSetsAndClearsStatus(t *testing.T) {
+	c, reqs := fakeGitHub(t, func(req gqlRequest) string { return `{"data":{}}` })
+
+	if err := c.MoveCard(context.Background(), "I1", "P1/F1/o2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MoveCard(context.Background(), "I1", "P1/F1/"); err != nil {
+		t.Fatal(err)
+	}
+
+	set, clear := (*reqs)[0], (*reqs)[1]
+	if !strings.Contains(set.Query, "updateProjectV2ItemFieldValue") || set.Variables["option"] != "o2" {
+		t.Errorf("set = %+v", set)
+	}
+	if !strings.Contains(clear.Query, "clearProjectV2ItemFieldValue") {
+		t.Errorf("clear = %+v", clear)
+	}
+	if _, ok := clear.Variables["option"]; ok {
+		t.Errorf("clear sent an option: %+v", clear.Variables)
+	}
+	for _, r := range []gqlRequest{set, clear} {
+		if r.Variables["project"] != "P1" || r.Variables["item"] != "I1" || r.Variables["field"] != "F1" {
+			t.Errorf("variables = %+v", r.Variables)
+		}
+	}
+}
+
+func TestMoveCardRejectsBadListID(t *testing.T) {
+	c, reqs := fakeGitHub(t, func(req gqlRequest) string { return `{"data":{}}` })
+
+	if err := c.MoveCard(context.Background(), "I1", "not-a-list"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(*reqs) != 0 {
+		t.Fatalf("sent %d requests", len(*reqs))
+	}
+}
+
+func TestUpdateCardEditsDraftIssue(t *testing.T) {
+	c, reqs := fakeGitHub(t, func(req gqlRequest) string {
+		if strings.Contains(req.Query, "ProjectV2Item") {
+			return `{"data":{"node":{"project":{"id":"P1"},"content":{"__typename":"DraftIssue","id":"D1"}}}}`
+		}
+		return `{"data":{}}`
+	})
+
+	name := "Renamed"
+	if err := c.UpdateCard(context.Background(), "I3", board.CardUpdate{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	if len(*reqs) != 2 {
+		t.Fatalf("sent %d requests", len(*reqs))
+	}
+	m := (*reqs)[1]
+	if !strings.Contains(m.Query, "updateProjectV2DraftIssue") {
+		t.Fatalf("mutation = %s", m.Query)
+	}
+	input, _ := m.Variables["input"].(map[string]any)
+	if input["draftIssueId"] != "D1" || input["title"] != "Renamed" {
+		t.Errorf("input = %+v", input)
+	}
+	if _, ok := input["body"]; ok {
+		t.Errorf("unset description was sent: %+v", input)
+	}
+}
+
+func TestUpdateCardEmptyIsNoop(t *testing.T) {
+	c, reqs := fakeGitHub(t, func(req gqlRequest) string { return `{"data":{}}` })
+
+	if err := c.UpdateCard(context.Background(), "I3", board.CardUpdate{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(*reqs) != 0 {
+		t.Fatalf("sent %d requests", len(*reqs))
+	}
+}
+
+func TestQueryReturnsGraphQLErrors(t *testing.T) {
+	c, _ := fakeGitHub(t, func(req gqlRequest) string {
+		return `{"data":null,"errors":[{"message":"Could not resolve to a node"},{"message":"insufficient scopes"}]}`
+	})
+
+	_, err := c.Lists(context.Background(), "P1")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := err.Error(); !strings.Contains(got, "Could not resolve to a node") || !strings.Contains(got, "insufficient scopes") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestQueryReportsHTTPStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "tok", "", "")
+
+	if _, err := c.Boards(context.Background()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package jira
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// jira v3 carries rich text as atlassian document format (adf). aboard only
+// deals in plain text, so these helpers convert in both directions and drop
+// formatting that has no plain equivalent.
+
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text,omitempty"`
+	Content []adfNode `json:"content,omitempty"`
+	Version int       `json:"version,omitempty"`
+}
+
+// textToADF turns plain text into a doc with one paragraph per line.
+func textToADF(s string) adfNode {
+	doc := adfNode{Type: "doc", Version: 1}
+	for _, line := range strings.Split(s, "\n") {
+		p := adfNode{Type: "paragraph"}
+		if line != "" {
+			p.Content = []adfNode{{Type: "text", Text: line}}
+		}
+		doc.Content = append(doc.Content, p)
+	}
+	return doc
+}
+
+// descriptionText accepts either an adf doc (v3) or a plain string (agile
+// endpoints on some sites) and returns plain text.
+func descriptionText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	var b strings.Builder
+	writeADF(&b, doc)
+	return strings.TrimSpace(b.String())
+}
+
+func writeADF(b *strings.Builder, n adfNode) {
+	switch n.Type {
+	case "text":
+		b.WriteString(n.Text)
+		return
+	case "hardBreak":
+		b.WriteString("\n")
+		return
+	case "listItem":
+		b.WriteString("- ")
+	}
+	for _, child := range n.Content {
+		writeADF(b, child)
+	}
+	switch n.Type {
+	case "paragraph", "heading", "codeBlock", "blockquote":
+		b.WriteString("\n")
+	}
+}
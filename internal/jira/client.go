@@ -0,0 +1,489 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// Client maps jira cloud onto the kanban model: agile boards are boards,
+// board columns are lists and issues are cards. moving a card runs the
+// workflow transition that lands the issue in one of the column's statuses.
+type Client struct {
+	baseURL   string
+	email     string
+	token     string
+	issueType string
+	http      *http.Client
+
+	mu      sync.Mutex
+	columns map[string][]column // board id -> column config
+	project map[string]string   // board id -> project key
+}
+
+type column struct {
+	Name     string
+	Statuses []string
+}
+
+var _ board.BoardProvider = (*Client)(nil)
+
+// NewClient targets a jira cloud site such as https://acme.atlassian.net.
+// issueType is used for new cards and defaults to Task.
+func NewClient(baseURL, email, token, issueType string) *Client {
+	if issueType == "" {
+		issueType = "Task"
+	}
+	return &Client{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		email:     email,
+		token:     token,
+		issueType: issueType,
+		http: &http.Client{
+			Timeout: 12 * time.Second,
+		},
+		columns: make(map[string][]column),
+		project: make(map[string]string),
+	}
+}
+
+func (c *Client) Name() string {
+	return "jira"
+}
+
+func (c *Client) CanAuth() bool {
+	return c.baseURL != "" && c.email != "" && c.token != ""
+}
+
+func (c *Client) AuthHelp() []string {
+	return []string{
+		"set these env vars:",
+		"  JIRA_BASE_URL   (e.g. https://acme.atlassian.net)",
+		"  JIRA_EMAIL",
+		"  JIRA_API_TOKEN",
+		"  JIRA_ISSUE_TYPE (optional, default Task)",
+	}
+}
+
+type boardPage struct {
+	Values []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"values"`
+	IsLast bool `json:"isLast"`
+}
+
+func (c *Client) Boards(ctx context.Context) ([]board.Board, error) {
+	if !c.CanAuth() {
+		return nil, errors.New("missing JIRA_BASE_URL, JIRA_EMAIL or JIRA_API_TOKEN")
+	}
+
+	var boards []board.Board
+	for startAt := 0; ; {
+		q := url.Values{"startAt": {strconv.Itoa(startAt)}, "maxResults": {"50"}}
+		var page boardPage
+		if err := c.do(ctx, http.MethodGet, "/rest/agile/1.0/board?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, v := range page.Values {
+			boards = append(boards, board.Board{ID: strconv.Itoa(v.ID), Name: v.Name})
+		}
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+		startAt += len(page.Values)
+	}
+	return boards, nil
+}
+
+type boardConfig struct {
+	ColumnConfig struct {
+		Columns []struct {
+			Name     string `json:"name"`
+			Statuses []struct {
+				ID string `json:"id"`
+			} `json:"statuses"`
+		} `json:"columns"`
+	} `json:"columnConfig"`
+	Location struct {
+		ProjectKey string `json:"projectKey"`
+	} `json:"location"`
+}
+
+func (c *Client) Lists(ctx context.Context, boardID string) ([]board.List, error) {
+	cols, err := c.loadColumns(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	lists := make([]board.List, 0, len(cols))
+	for i, col := range cols {
+		lists = append(lists, board.List{ID: listID(boardID, i), Name: col.Name})
+	}
+	return lists, nil
+}
+
+type issuePage struct {
+	Issues []issue `json:"issues"`
+	Total  int     `json:"total"`
+}
+
+type issue struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Fields struct {
+		Summary     string          `json:"summary"`
+		Description json.RawMessage `json:"description"`
+		Status      struct {
+			ID string `json:"id"`
+		} `json:"status"`
+	} `json:"fields"`
+}
+
+func (c *Client) Cards(ctx context.Context, boardID string) ([]board.Card, error) {
+	if boardID == "" {
+		return nil, errors.New("board id is required")
+	}
+	cols, err := c.loadColumns(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	colByStatus := make(map[string]int)
+	for i, col := range cols {
+		for _, s := range col.Statuses {
+			colByStatus[s] = i
+		}
+	}
+
+	var cards []board.Card
+	for startAt := 0; ; {
+		q := url.Values{
+			"startAt":    {strconv.Itoa(startAt)},
+			"maxResults": {"100"},
+			"fields":     {"summary,description,status"},
+		}
+		var page issuePage
+		if err := c.do(ctx, http.MethodGet, "/rest/agile/1.0/board/"+url.PathEscape(boardID)+"/issue?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		for _, is := range page.Issues {
+			idx, ok := colByStatus[is.Fields.Status.ID]
+			if !ok {
+				// status isn't mapped to a column, jira hides these too
+				continue
+			}
+			cards = append(cards, board.Card{
+				ID:       is.Key,
+				Name:     is.Fields.Summary,
+				Desc:     descriptionText(is.Fields.Description),
+				URL:      c.browseURL(is.Key),
+				ShortURL: c.browseURL(is.Key),
+				IDList:   listID(boardID, idx),
+				ListName: cols[idx].Name,
+			})
+		}
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+	return cards, nil
+}
+
+type transitionsResponse struct {
+	Transitions []struct {
+		ID string `json:"id"`
+		To struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"to"`
+	} `json:"transitions"`
+}
+
+// MoveCard runs the first available transition whose target status belongs
+// to the destination column.
+func (c *Client) MoveCard(ctx context.Context, cardID, listID string) error {
+	boardID, idx, err := parseListID(listID)
+	if err != nil {
+		return err
+	}
+	cols, err := c.columnsFor(ctx, boardID)
+	if err != nil {
+		return err
+	}
+	if idx >= len(cols) {
+		return fmt.Errorf("list %s not found", listID)
+	}
+	target := cols[idx]
+
+	var tr transitionsResponse
+	if err := c.do(ctx, http.MethodGet, "/rest/api/3/issue/"+url.PathEscape(cardID)+"/transitions", nil, &tr); err != nil {
+		return err
+	}
+	for _, t := range tr.Transitions {
+		for _, s := range target.Statuses {
+			if t.To.ID == s {
+				body := map[string]any{"transition": map[string]string{"id": t.ID}}
+				return c.do(ctx, http.MethodPost, "/rest/api/3/issue/"+url.PathEscape(cardID)+"/transitions", body, nil)
+			}
+		}
+	}
+	return fmt.Errorf("no workflow transition moves %s to %q", cardID, target.Name)
+}
+
+func (c *Client) UpdateCard(ctx context.Context, cardID string, fields board.CardUpdate) error {
+	if fields.Empty() {
+		return nil
+	}
+	f := map[string]any{}
+	if fields.Name != nil {
+		f["summary"] = *fields.Name
+	}
+	if fields.Desc != nil {
+		f["description"] = textToADF(*fields.Desc)
+	}
+	return c.do(ctx, http.MethodPut, "/rest/api/3/issue/"+url.PathEscape(cardID), map[string]any{"fields": f}, nil)
+}
+
+func (c *Client) AddComment(ctx context.Context, cardID, text string) error {
+	body := map[string]any{"body": textToADF(text)}
+	return c.do(ctx, http.MethodPost, "/rest/api/3/issue/"+url.PathEscape(cardID)+"/comment", body, nil)
+}
+
+// ArchiveCard uses jira's issue archiving, which needs a premium plan;
+// other sites get ErrUnsupported.
+func (c *Client) ArchiveCard(ctx context.Context, cardID string) error {
+	return c.archive(ctx, "archive", cardID)
+}
+
+func (c *Client) CreateCard(ctx context.Context, listID, name string) (*board.Card, error) {
+	boardID, idx, err := parseListID(listID)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := c.columnsFor(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if idx >= len(cols) {
+		return nil, fmt.Errorf("list %s not found", listID)
+	}
+
+	c.mu.Lock()
+	projectKey := c.project[boardID]
+	c.mu.Unlock()
+	if projectKey == "" {
+		return nil, fmt.Errorf("board %s is not tied to a single project", boardID)
+	}
+
+	body := map[string]any{"fields": map[string]any{
+		"project":   map[string]string{"key": projectKey},
+		"summary":   name,
+		"issuetype": map[string]string{"name": c.issueType},
+	}}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/rest/api/3/issue", body, &created); err != nil {
+		return nil, err
+	}
+
+	card := &board.Card{
+		ID:       created.Key,
+		Name:     name,
+		URL:      c.browseURL(created.Key),
+		ShortURL: c.browseURL(created.Key),
+		IDList:   listID,
+		ListName: cols[idx].Name,
+	}
+	// new issues start in the workflow's initial status; walk them over if
+	// the card was created in a later column
+	if idx > 0 {
+		if err := c.MoveCard(ctx, created.Key, listID); err != nil {
+			return card, fmt.Errorf("created %s but could not move it: %w", created.Key, err)
+		}
+	}
+	return card, nil
+}
+
+func (c *Client) CreateList(ctx context.Context, boardID, name string) (*board.List, error) {
+	return nil, fmt.Errorf("jira columns are edited in board settings: %w", board.ErrUnsupported)
+}
+
+func (c *Client) ArchiveList(ctx context.Context, listID string) error {
+	return fmt.Errorf("jira columns are edited in board settings: %w", board.ErrUnsupported)
+}
+
+func (c *Client) RestoreCard(ctx context.Context, cardID string) error {
+	return c.archive(ctx, "unarchive", cardID)
+}
+
+// archive runs the bulk archive or unarchive endpoint for one issue. it
+// answers 200 even when nothing was archived, with the reasons per issue.
+func (c *Client) archive(ctx context.Context, op, cardID string) error {
+	var res struct {
+		Updated int `json:"numberOfIssuesUpdated"`
+		Errors  map[string]struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	body := map[string]any{"issueIdsOrKeys": []string{cardID}}
+	err := c.do(ctx, http.MethodPut, "/rest/api/3/issue/"+op, body, &res)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && (apiErr.status == http.StatusPreconditionFailed || apiErr.status == http.StatusNotFound) {
+		// sites without premium don't have archiving
+		return fmt.Errorf("issue archiving needs jira premium: %w", board.ErrUnsupported)
+	}
+	if err != nil {
+		return err
+	}
+	if res.Updated > 0 {
+		return nil
+	}
+	reasons := make([]string, 0, len(res.Errors))
+	for _, e := range res.Errors {
+		reasons = append(reasons, e.Message)
+	}
+	sort.Strings(reasons)
+	if len(reasons) == 0 {
+		return fmt.Errorf("jira did not %s %s", op, cardID)
+	}
+	return fmt.Errorf("jira did not %s %s: %s", op, cardID, strings.Join(reasons, "; "))
+}
+
+func (c *Client) RestoreList(ctx context.Context, listID string) error {
+	return fmt.Errorf("jira columns are edited in board settings: %w", board.ErrUnsupported)
+}
+
+func (c *Client) loadColumns(ctx context.Context, boardID string) ([]column, error) {
+	if !c.CanAuth() {
+		return nil, errors.New("missing JIRA_BASE_URL, JIRA_EMAIL or JIRA_API_TOKEN")
+	}
+	var cfg boardConfig
+	if err := c.do(ctx, http.MethodGet, "/rest/agile/1.0/board/"+url.PathEscape(boardID)+"/configuration", nil, &cfg); err != nil {
+		return nil, err
+	}
+	cols := make([]column, 0, len(cfg.ColumnConfig.Columns))
+	for _, col := range cfg.ColumnConfig.Columns {
+		ids := make([]string, 0, len(col.Statuses))
+		for _, s := range col.Statuses {
+			ids = append(ids, s.ID)
+		}
+		cols = append(cols, column{Name: col.Name, Statuses: ids})
+	}
+
+	c.mu.Lock()
+	c.columns[boardID] = cols
+	c.project[boardID] = cfg.Location.ProjectKey
+	c.mu.Unlock()
+	return cols, nil
+}
+
+// columnsFor returns the cached column config, fetching it on first use.
+func (c *Client) columnsFor(ctx context.Context, boardID string) ([]column, error) {
+	c.mu.Lock()
+	cols, ok := c.columns[boardID]
+	c.mu.Unlock()
+	if ok {
+		return cols, nil
+	}
+	return c.loadColumns(ctx, boardID)
+}
+
+func (c *Client) browseURL(key string) string {
+	return c.baseURL + "/browse/" + url.PathEscape(key)
+}
+
+// list ids are "<board id>/<column index>" since jira columns have no id of their own.
+func listID(boardID string, idx int) string {
+	return boardID + "/" + strconv.Itoa(idx)
+}
+
+func parseListID(id string) (string, int, error) {
+	boardID, rawIdx, ok := strings.Cut(id, "/")
+	idx, err := strconv.Atoi(rawIdx)
+	if !ok || boardID == "" || err != nil || idx < 0 {
+		return "", 0, fmt.Errorf("invalid jira list id %q", id)
+	}
+	return boardID, idx, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, target any) error {
+	var r io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, r)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.email, c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return responseError(resp)
+	}
+	if target == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// apiError is a non-2xx answer from jira.
+type apiError struct {
+	status int
+	msg    string
+}
+
+func (e *apiError) Error() string {
+	return e.msg
+}
+
+// responseError includes jira's own error text when the body carries one,
+// which is usually more useful than the bare status (e.g. workflow validators).
+func responseError(resp *http.Response) error {
+	var payload struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&payload)
+
+	msgs := append([]string(nil), payload.ErrorMessages...)
+	fields := make([]string, 0, len(payload.Errors))
+	for field := range payload.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		msgs = append(msgs, field+": "+payload.Errors[field])
+	}
+	if len(msgs) == 0 {
+		return &apiError{status: resp.StatusCode, msg: "jira returned " + resp.Status}
+	}
+	return &apiError{status: resp.StatusCode, msg: fmt.Sprintf("jira returned %s: %s", resp.Status, strings.Join(msgs, "; "))}
+}
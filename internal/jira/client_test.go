@@ -0,0 +1,228 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+type jiraRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   map[string]any
+}
+
+// fakeJira answers requests with respond's status and body, recording each
+// one.
+func fakeJira(t *testing.T, respond func(req jiraRequest) (int, string)) (*Client, *[]jiraRequest) {
+	t.Helper()
+	var reqs []jiraRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@acme.test" || pass != "tok" {
+			t.Errorf("basic auth = %q, %q", user, pass)
+		}
+		req := jiraRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery}
+		if raw, _ := io.ReadAll(r.Body); len(raw) > 0 {
+			if err := json.Unmarshal(raw, &req.Body); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+		}
+		reqs = append(reqs, req)
+		status, body := respond(req)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL, "me@acme.test", "tok", ""), &reqs
+}
+
+const configResponse = `{
+	"location": {"projectKey": "ACME"},
+	"columnConfig": {"columns": [
+		{"name": "To Do", "statuses": [{"id": "1"}]},
+		{"name": "In Progress", "statuses": [{"id": "3"}, {"id": "4"}]},
+		{"name": "Done", "statuses": [{"id": "10"}]}
+	]}}`
+
+func TestBoardsFollowsPages(t *testing.T) {
+	c, reqs := fakeJira(t, func(req jiraRequest) (int, string) {
+		if strings.Contains(req.Query, "startAt=0") {
+			return 200, `{"values":[{"id":1,"name":"Web"},{"id":2,"name":"Mobile"}],"isLast":false}`
+		}
+		return 200, `{"values":[{"id":3,"name":"Ops"}],"isLast":true}`
+	})
+
+	boards, err := c.Boards(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []board.Board{{ID: "1", Name: "Web"}, {ID: "2", Name: "Mobile"}, {ID: "3", Name: "Ops"}}
+	if len(boards) != len(want) {
+		t.Fatalf("boards = %+v", boards)
+	}
+	for i := range want {
+		if boards[i] != want[i] {
+			t.Errorf("boards[%d] = %+v, want %+v", i, boards[i], want[i])
+		}
+	}
+	if len(*reqs) != 2 || !strings.Contains((*reqs)[1].Query, "startAt=2") {
+		t.Fatalf("requests = %+v", *reqs)
+	}
+}
+
+func TestListsAreColumns(t *testing.T) {
+	c, reqs := fakeJira(t, func(req jiraRequest) (int, string) { return 200, configResponse })
+
+	lists, err := c.Lists(context.Background(), "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []board.List{{ID: "7/0", Name: "To Do"}, {ID: "7/1", Name: "In Progress"}, {ID: "7/2", Name: "Done"}}
+	if len(lists) != len(want) {
+		t.Fatalf("lists = %+v", lists)
+	}
+	for i := range want {
+		if lists[i] != want[i] {
+			t.Errorf("lists[%d] = %+v, want %+v", i, lists[i], want[i])
+		}
+	}
+	if got := (*reqs)[0].Path; got != "/rest/agile/1.0/board/7/configuration" {
+		t.Errorf("path = %s", got)
+	}
+}
+
+func TestCardsMapStatusesToColumns(t *testing.T) {
+	c, reqs := fakeJira(t, func(req jiraRequest) (int, string) {
+		if strings.HasSuffix(req.Path, "/configuration") {
+			return 200, configResponse
+		}
+		if strings.Contains(req.Query, "startAt=0") {
+			return 200, `{"total":3,"issues":[
+				{"id":"101","key":"ACME-1","fields":{"summary":"Login","status":{"id":"4"},
+					"description":{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"steps"}]}]}}},
+				{"id":"102","key":"ACME-2","fields":{"summary":"Hidden","status":{"id":"99"}}}
+			]}`
+		}
+		return 200, `{"total":3,"issues":[{"id":"103","key":"ACME-3","fields":{"summary":"Ship","status":{"id":"10"}}}]}`
+	})
+
+	cards, err := c.Cards(context.Background(), "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 {
+		t.Fatalf("cards = %+v", cards)
+	}
+	if got := cards[0]; got.ID != "ACME-1" || got.IDList != "7/1" || got.ListName != "In Progress" || got.Desc != "steps" {
+		t.Errorf("cards[0] = %+v", got)
+	}
+	if got := cards[0].URL; !strings.HasSuffix(got, "/browse/ACME-1") {
+		t.Errorf("url = %s", got)
+	}
+	if got := cards[1]; got.ID != "ACME-3" || got.IDList != "7/2" || got.ListName != "Done" {
+		t.Errorf("cards[1] = %+v", got)
+	}
+	if got := (*reqs)[2].Query; !strings.Contains(got, "startAt=2") {
+		t.Errorf("second page query = %s", got)
+	}
+}
+
+func TestListsEscapeBoardID(t *testing.T) {
+	c, reqs := fakeJira(t, func(req jiraRequest) (int, string) { return 200, configResponse })
+
+	c.Lists(context.Background(), "7?x=1")
+	if got := (*reqs)[0]; got.Path != "/rest/agile/1.0/board/7?x=1/configuration" || got.Query != "" {
+		t.Fatalf("request = %+v", got)
+	}
+}
+
+func TestMoveCardRunsTransitionToColumn(t *testing.T) {
+	c, reqs := fakeJira(t, func(req jiraRequest) (int, string) {
+		switch {
+		case strings.HasSuffix(req.Path, "/configuration"):
+			return 200, configResponse
+		case req.Method == http.MethodGet:
+			return 200, `{"transitions":[
+				{"id":"11","to":{"id":"1","name":"To Do"}},
+				{"id":"21","to":{"id":"4","name":"Review"}},
+				{"id":"31","to":{"id":"10","name":"Done"}}]}`
+		}
+		return 204, ""
+	})
+
+	if err := c.MoveCard(context.Background(), "ACME-1", "7/1"); err != nil {
+		t.Fatal(err)
+	}
+	post := (*reqs)[len(*reqs)-1]
+	if post.Method != http.MethodPost || post.Path != "/rest/api/3/issue/ACME-1/transitions" {
+		t.Fatalf("request = %+v", post)
+	}
+	tr, _ := post.Body["transition"].(map[string]any)
+	if tr["id"] != "21" {
+		t.Fatalf("transition = %+v", post.Body)
+	}
+}
+
+func TestMoveCardWithoutTransition(t *testing.T) {
+	c, reqs := fakeJira(t, func(req jiraRequest) (int, string) {
+		if strings.HasSuffix(req.Path, "/configuration") {
+			return 200, configResponse
+		}
+		return 200, `{"transitions":[{"id":"11","to":{"id":"1","name":"To Do"}}]}`
+	})
+
+	err := c.MoveCard(context.Background(), "ACME-1", "7/2")
+	if err == nil || !strings.Contains(err.Error(), `"Done"`) {
+		t.Fatalf("err = %v", err)
+	}
+	for _, r := range *reqs {
+		if r.Method == http.MethodPost {
+			t.Fatalf("ran a transition: %+v", r)
+		}
+	}
+}
+
+func TestArchiveCardWithoutPremium(t *testing.T) {
+	c, _ := fakeJira(t, func(req jiraRequest) (int, string) {
+		return 412, `{"errorMessages":["Archiving issues is only available on Jira Premium."]}`
+	})
+
+	if err := c.ArchiveCard(context.Background(), "ACME-1"); !errors.Is(err, board.ErrUnsupported) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestArchiveCardReportsSkippedIssue(t *testing.T) {
+	c, reqs := fakeJira(t, func(req jiraRequest) (int, string) {
+		return 200, `{"numberOfIssuesUpdated":0,"errors":{"issueIsSubtask":{"count":1,"issueIdsOrKeys":["ACME-1"],"message":"Issue is subtask."}}}`
+	})
+
+	err := c.ArchiveCard(context.Background(), "ACME-1")
+	if err == nil || !strings.Contains(err.Error(), "Issue is subtask.") {
+		t.Fatalf("err = %v", err)
+	}
+	if got := (*reqs)[0]; got.Method != http.MethodPut || got.Path != "/rest/api/3/issue/archive" {
+		t.Fatalf("request = %+v", got)
+	}
+}
+
+func TestErrorsCarryJiraMessages(t *testing.T) {
+	c, _ := fakeJira(t, func(req jiraRequest) (int, string) {
+		return 400, `{"errorMessages":["Issue does not exist"],"errors":{"summary":"required"}}`
+	})
+
+	name := "x"
+	err := c.UpdateCard(context.Background(), "ACME-9", board.CardUpdate{Name: &name})
+	if err == nil || !strings.Contains(err.Error(), "Issue does not exist; summary: required") {
+		t.Fatalf("err = %v", err)
+	}
+}
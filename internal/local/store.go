@@ -0,0 +1,377 @@
+package local
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/codywilliamson/aboard/internal/board"
+	_ "modernc.org/sqlite"
+)
+
+// Store is an offline BoardProvider backed by a single sqlite file.
+type Store struct {
+	db   *sql.DB
+	path string
+}
+
+var _ board.BoardProvider = (*Store)(nil)
+
+var _ board.CommentDeleter = (*Store)(nil)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS boards (
+	id         TEXT PRIMARY KEY,
+	name       TEXT NOT NULL,
+	closed     INTEGER NOT NULL DEFAULT 0,
+	created_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS lists (
+	id         TEXT PRIMARY KEY,
+	board_id   TEXT NOT NULL REFERENCES boards(id),
+	name       TEXT NOT NULL,
+	pos        REAL NOT NULL,
+	closed     INTEGER NOT NULL DEFAULT 0,
+	created_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS cards (
+	id         TEXT PRIMARY KEY,
+	list_id    TEXT NOT NULL REFERENCES lists(id),
+	name       TEXT NOT NULL,
+	desc       TEXT NOT NULL DEFAULT '',
+	pos        REAL NOT NULL,
+	closed     INTEGER NOT NULL DEFAULT 0,
+	created_at TEXT NOT NULL,
+	updated_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS comments (
+	id         TEXT PRIMARY KEY,
+	card_id    TEXT NOT NULL REFERENCES cards(id),
+	text       TEXT NOT NULL,
+	created_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_lists_board ON lists(board_id);
+CREATE INDEX IF NOT EXISTS idx_cards_list ON cards(list_id);
+CREATE INDEX IF NOT EXISTS idx_comments_card ON comments(card_id);
+`
+
+// DefaultPath returns <user config dir>/aboard/aboard.db.
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "aboard.db"
+	}
+	return filepath.Join(dir, "aboard", "aboard.db")
+}
+
+// Open opens (creating if needed) the database at path. a fresh database is
+// seeded with one board so there is something to look at on first run.
+func Open(path string) (*Store, error) {
+	if path == "" {
+		path = DefaultPath()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	dsn := "file:" + filepath.ToSlash(path) + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// mutations arrive concurrently via tea.Batch; sqlite only has one writer anyway
+	db.SetMaxOpenConns(1)
+
+	s := &Store{db: db, path: path}
+	if err := s.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) Name() string {
+	return "local"
+}
+
+func (s *Store) CanAuth() bool {
+	return s.db != nil
+}
+
+func (s *Store) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, schema); err != nil {
+		return err
+	}
+
+	var n int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM boards`).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	b, err := s.CreateBoard(ctx, "Personal")
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{"To Do", "Doing", "Done"} {
+		if _, err := s.CreateList(ctx, b.ID, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) Boards(ctx context.Context) ([]board.Board, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, name FROM boards WHERE closed = 0 ORDER BY created_at, name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var boards []board.Board
+	for rows.Next() {
+		var b board.Board
+		if err := rows.Scan(&b.ID, &b.Name); err != nil {
+			return nil, err
+		}
+		boards = append(boards, b)
+	}
+	return boards, rows.Err()
+}
+
+func (s *Store) Lists(ctx context.Context, boardID string) ([]board.List, error) {
+	if boardID == "" {
+		return nil, errors.New("board id is required")
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, name FROM lists WHERE board_id = ? AND closed = 0 ORDER BY pos`, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lists []board.List
+	for rows.Next() {
+		var l board.List
+		if err := rows.Scan(&l.ID, &l.Name); err != nil {
+			return nil, err
+		}
+		lists = append(lists, l)
+	}
+	return lists, rows.Err()
+}
+
+func (s *Store) Cards(ctx context.Context, boardID string) ([]board.Card, error) {
+	if boardID == "" {
+		return nil, errors.New("board id is required")
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.id, c.name, c.desc, c.list_id, l.name
+		FROM cards c JOIN lists l ON l.id = c.list_id
+		WHERE l.board_id = ? AND l.closed = 0 AND c.closed = 0
+		ORDER BY l.pos, c.pos`, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cards []board.Card
+	for rows.Next() {
+		var c board.Card
+		if err := rows.Scan(&c.ID, &c.Name, &c.Desc, &c.IDList, &c.ListName); err != nil {
+			return nil, err
+		}
+		cards = append(cards, c)
+	}
+	return cards, rows.Err()
+}
+
+func (s *Store) MoveCard(ctx context.Context, cardID, listID string) error {
+	if err := s.requireRow(ctx, "lists", listID); err != nil {
+		return err
+	}
+	pos, err := s.nextPos(ctx, "cards", "list_id", listID)
+	if err != nil {
+		return err
+	}
+	return s.execOne(ctx, "card", cardID,
+		`UPDATE cards SET list_id = ?, pos = ?, updated_at = ? WHERE id = ?`,
+		listID, pos, now(), cardID)
+}
+
+func (s *Store) UpdateCard(ctx context.Context, cardID string, fields board.CardUpdate) error {
+	if fields.Empty() {
+		return nil
+	}
+	var sets []string
+	var args []any
+	if fields.Name != nil {
+		sets = append(sets, "name = ?")
+		args = append(args, *fields.Name)
+	}
+	if fields.Desc != nil {
+		sets = append(sets, "desc = ?")
+		args = append(args, *fields.Desc)
+	}
+	sets = append(sets, "updated_at = ?")
+	args = append(args, now(), cardID)
+	return s.execOne(ctx, "card", cardID,
+		`UPDATE cards SET `+strings.Join(sets, ", ")+` WHERE id = ?`, args...)
+}
+
+func (s *Store) AddComment(ctx context.Context, cardID, text string) error {
+	_, err := s.PostComment(ctx, cardID, text)
+	return err
+}
+
+func (s *Store) PostComment(ctx context.Context, cardID, text string) (string, error) {
+	if err := s.requireRow(ctx, "cards", cardID); err != nil {
+		return "", err
+	}
+	id := newID()
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT INTO comments (id, card_id, text, created_at) VALUES (?, ?, ?, ?)`,
+		id, cardID, text, now()); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *Store) DeleteComment(ctx context.Context, cardID, commentID string) error {
+	return s.execOne(ctx, "comment", commentID,
+		`DELETE FROM comments WHERE id = ? AND card_id = ?`, commentID, cardID)
+}
+
+func (s *Store) ArchiveCard(ctx context.Context, cardID string) error {
+	return s.execOne(ctx, "card", cardID,
+		`UPDATE cards SET closed = 1, updated_at = ? WHERE id = ?`, now(), cardID)
+}
+
+func (s *Store) CreateCard(ctx context.Context, listID, name string) (*board.Card, error) {
+	var listName string
+	err := s.db.QueryRowContext(ctx, `SELECT name FROM lists WHERE id = ?`, listID).Scan(&listName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("list %s not found", listID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pos, err := s.nextPos(ctx, "cards", "list_id", listID)
+	if err != nil {
+		return nil, err
+	}
+
+	card := &board.Card{ID: newID(), Name: name, IDList: listID, ListName: listName}
+	stamp := now()
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO cards (id, list_id, name, pos, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		card.ID, listID, name, pos, stamp, stamp)
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func (s *Store) CreateList(ctx context.Context, boardID, name string) (*board.List, error) {
+	if err := s.requireRow(ctx, "boards", boardID); err != nil {
+		return nil, err
+	}
+	pos, err := s.nextPos(ctx, "lists", "board_id", boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &board.List{ID: newID(), Name: name}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO lists (id, board_id, name, pos, created_at) VALUES (?, ?, ?, ?, ?)`,
+		list.ID, boardID, name, pos, now())
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Store) ArchiveList(ctx context.Context, listID string) error {
+	return s.execOne(ctx, "list", listID, `UPDATE lists SET closed = 1 WHERE id = ?`, listID)
+}
+
+func (s *Store) RestoreCard(ctx context.Context, cardID string) error {
+	return s.execOne(ctx, "card", cardID,
+		`UPDATE cards SET closed = 0, updated_at = ? WHERE id = ?`, now(), cardID)
+}
+
+func (s *Store) RestoreList(ctx context.Context, listID string) error {
+	return s.execOne(ctx, "list", listID, `UPDATE lists SET closed = 0 WHERE id = ?`, listID)
+}
+
+// CreateBoard is local-only: remote providers manage boards in their own ui.
+func (s *Store) CreateBoard(ctx context.Context, name string) (*board.Board, error) {
+	b := &board.Board{ID: newID(), Name: name}
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO boards (id, name, created_at) VALUES (?, ?, ?)`, b.ID, name, now())
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// execOne runs a single-row update and reports a not-found error when nothing matched.
+func (s *Store) execOne(ctx context.Context, kind, id, query string, args ...any) error {
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%s %s not found", kind, id)
+	}
+	return nil
+}
+
+func (s *Store) requireRow(ctx context.Context, table, id string) error {
+	var one int
+	err := s.db.QueryRowContext(ctx, `SELECT 1 FROM `+table+` WHERE id = ?`, id).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s %s not found", strings.TrimSuffix(table, "s"), id)
+	}
+	return err
+}
+
+func (s *Store) nextPos(ctx context.Context, table, parentCol, parentID string) (float64, error) {
+	var pos sql.NullFloat64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT MAX(pos) FROM `+table+` WHERE `+parentCol+` = ?`, parentID).Scan(&pos)
+	if err != nil {
+		return 0, err
+	}
+	if !pos.Valid {
+		return 1024, nil
+	}
+	return pos.Float64 + 1024, nil
+}
+
+func newID() string {
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,214 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// a card file looks like:
+//
+//	---
+//	id: 3f2a9c01b7de
+//	title: Fix login redirect
+//	created: 2026-02-14 09:30 UTC
+//	---
+//
+//	description in plain markdown
+//
+//	## Comments
+//
+//	### 2026-02-14 10:02 UTC
+//	comment text
+//
+// cards without front-matter are still read: the title falls back to the
+// first "# heading" or the file name, and the id to the file name.
+
+const commentsHeading = "## Comments"
+
+type cardFile struct {
+	path     string
+	meta     frontMatter
+	body     string
+	comments []comment
+}
+
+type comment struct {
+	when string
+	text string
+}
+
+// frontMatter keeps key order so rewrites produce minimal diffs.
+type frontMatter struct {
+	keys   []string
+	values map[string]string
+}
+
+func (fm *frontMatter) get(key string) string {
+	return fm.values[key]
+}
+
+func (fm *frontMatter) set(key, value string) {
+	if fm.values == nil {
+		fm.values = make(map[string]string)
+	}
+	if _, ok := fm.values[key]; !ok {
+		fm.keys = append(fm.keys, key)
+	}
+	fm.values[key] = value
+}
+
+func readCard(path string) (*cardFile, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &cardFile{path: path}
+	text := strings.ReplaceAll(string(raw), "\r\n", "\n")
+
+	if rest, ok := strings.CutPrefix(text, "---\n"); ok {
+		if end := strings.Index(rest, "\n---"); end >= 0 {
+			for _, line := range strings.Split(rest[:end], "\n") {
+				key, value, ok := strings.Cut(line, ":")
+				if !ok || strings.TrimSpace(key) == "" {
+					continue
+				}
+				f.meta.set(strings.TrimSpace(key), unquote(strings.TrimSpace(value)))
+			}
+			text = rest[end+len("\n---"):]
+		}
+	}
+
+	body, comments, _ := cutSection(text, commentsHeading)
+	f.body = strings.TrimSpace(body)
+	f.comments = parseComments(comments)
+
+	if f.meta.get("title") == "" {
+		if heading, rest, ok := strings.Cut(f.body, "\n"); ok && strings.HasPrefix(heading, "# ") {
+			f.meta.set("title", strings.TrimPrefix(heading, "# "))
+			f.body = strings.TrimSpace(rest)
+		} else if strings.HasPrefix(f.body, "# ") && !strings.Contains(f.body, "\n") {
+			f.meta.set("title", strings.TrimPrefix(f.body, "# "))
+			f.body = ""
+		}
+	}
+	return f, nil
+}
+
+// cutSection splits text at a line that is exactly heading.
+func cutSection(text, heading string) (before, after string, found bool) {
+	if strings.HasPrefix(text, heading+"\n") || text == heading {
+		return "", strings.TrimPrefix(text, heading), true
+	}
+	if i := strings.Index(text, "\n"+heading+"\n"); i >= 0 {
+		return text[:i], text[i+len(heading)+2:], true
+	}
+	if strings.HasSuffix(text, "\n"+heading) {
+		return strings.TrimSuffix(text, "\n"+heading), "", true
+	}
+	return text, "", false
+}
+
+func parseComments(section string) []comment {
+	var out []comment
+	var cur *comment
+	var lines []string
+	flush := func() {
+		if cur != nil {
+			cur.text = strings.TrimSpace(strings.Join(lines, "\n"))
+			out = append(out, *cur)
+		}
+		lines = nil
+	}
+	for _, line := range strings.Split(section, "\n") {
+		if when, ok := strings.CutPrefix(line, "### "); ok {
+			flush()
+			cur = &comment{when: strings.TrimSpace(when)}
+			continue
+		}
+		if cur != nil {
+			lines = append(lines, line)
+		}
+	}
+	flush()
+	return out
+}
+
+func (f *cardFile) id() string {
+	if id := f.meta.get("id"); id != "" {
+		return id
+	}
+	return strings.TrimSuffix(filepath.Base(f.path), ".md")
+}
+
+func (f *cardFile) title() string {
+	if t := f.meta.get("title"); t != "" {
+		return t
+	}
+	return strings.TrimSuffix(filepath.Base(f.path), ".md")
+}
+
+func (f *cardFile) card(root string, list board.List) board.Card {
+	rel, err := filepath.Rel(filepath.Dir(root), f.path)
+	if err != nil {
+		rel = f.path
+	}
+	return board.Card{
+		ID:       f.id(),
+		Name:     f.title(),
+		Desc:     f.body,
+		URL:      "file://" + filepath.ToSlash(f.path),
+		ShortURL: filepath.ToSlash(rel),
+		IDList:   list.ID,
+		ListName: list.Name,
+	}
+}
+
+func (f *cardFile) write() error {
+	var b strings.Builder
+	if len(f.meta.keys) > 0 {
+		b.WriteString("---\n")
+		for _, k := range f.meta.keys {
+			b.WriteString(k + ": " + quote(f.meta.values[k]) + "\n")
+		}
+		b.WriteString("---\n")
+	}
+	if f.body != "" {
+		b.WriteString("\n" + f.body + "\n")
+	}
+	if len(f.comments) > 0 {
+		b.WriteString("\n" + commentsHeading + "\n")
+		for _, c := range f.comments {
+			b.WriteString("\n### " + c.when + "\n" + c.text + "\n")
+		}
+	}
+
+	// write via rename so a crash never leaves a half-written card
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// quote wraps values that a yaml reader would otherwise misparse.
+func quote(v string) string {
+	if v == "" || strings.Contains(v, ": ") || strings.Contains(v, " #") ||
+		strings.ContainsAny(v, "\"\n") || strings.ContainsAny(v[:1], "'#-[]{}&*!|>%@`") ||
+		strings.TrimSpace(v) != v {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+	}
+	return v
+}
+
+func unquote(v string) string {
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		return strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n").Replace(v[1 : len(v)-1])
+	}
+	if len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'' {
+		return strings.ReplaceAll(v[1:len(v)-1], "''", "'")
+	}
+	return v
+}
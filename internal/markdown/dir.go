@@ -0,0 +1,500 @@
+package markdown
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// DefaultDir is the board directory looked up from the working directory.
+const DefaultDir = ".aboard"
+
+const (
+	archiveDir = ".archive"
+	keepFile   = ".gitkeep"
+)
+
+// Dir is a BoardProvider over a plain directory meant to live in a repo:
+// every subfolder is a list and every .md file in it is a card. lists are
+// ordered by folder name, so a numeric prefix ("01-To-Do") sets the order and
+// is hidden from the displayed name. archived items move under .archive/ so
+// nothing is lost and every change shows up as a normal diff.
+type Dir struct {
+	root string
+	mu   sync.Mutex
+}
+
+var _ board.BoardProvider = (*Dir)(nil)
+
+// Open uses root, or finds DefaultDir in the working directory or one of its
+// parents. when nothing is found the board is created next to the nearest
+// .git (or in the working directory) with a starter set of lists.
+func Open(root string) (*Dir, error) {
+	if root == "" {
+		found, err := findRoot()
+		if err != nil {
+			return nil, err
+		}
+		root = found
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(root); errors.Is(err, fs.ErrNotExist) {
+		for _, name := range []string{"01-To-Do", "02-Doing", "03-Done"} {
+			dir := filepath.Join(root, name)
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, err
+			}
+			// keep empty lists around in git
+			if err := os.WriteFile(filepath.Join(dir, keepFile), nil, 0o644); err != nil {
+				return nil, err
+			}
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	return &Dir{root: root}, nil
+}
+
+func findRoot() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	fallback := ""
+	for dir := wd; ; dir = filepath.Dir(dir) {
+		if info, err := os.Stat(filepath.Join(dir, DefaultDir)); err == nil && info.IsDir() {
+			return filepath.Join(dir, DefaultDir), nil
+		}
+		if fallback == "" {
+			if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+				fallback = filepath.Join(dir, DefaultDir)
+			}
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	if fallback == "" {
+		fallback = filepath.Join(wd, DefaultDir)
+	}
+	return fallback, nil
+}
+
+func (d *Dir) Name() string {
+	return "markdown"
+}
+
+func (d *Dir) CanAuth() bool {
+	return d.root != ""
+}
+
+// Boards returns the single board this directory holds, named after the
+// folder that contains it (usually the repo).
+func (d *Dir) Boards(ctx context.Context) ([]board.Board, error) {
+	name := filepath.Base(filepath.Dir(d.root))
+	if filepath.Base(d.root) != DefaultDir {
+		name = filepath.Base(d.root)
+	}
+	return []board.Board{{ID: d.root, Name: name}}, nil
+}
+
+func (d *Dir) Lists(ctx context.Context, boardID string) ([]board.List, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lists()
+}
+
+func (d *Dir) lists() ([]board.List, error) {
+	entries, err := os.ReadDir(d.root)
+	if err != nil {
+		return nil, err
+	}
+	var lists []board.List
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		lists = append(lists, board.List{ID: e.Name(), Name: listName(e.Name())})
+	}
+	return lists, nil
+}
+
+func (d *Dir) Cards(ctx context.Context, boardID string) ([]board.Card, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	lists, err := d.lists()
+	if err != nil {
+		return nil, err
+	}
+	var cards []board.Card
+	for _, l := range lists {
+		files, err := d.cardFiles(l.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			cards = append(cards, f.card(d.root, l))
+		}
+	}
+	return cards, nil
+}
+
+func (d *Dir) MoveCard(ctx context.Context, cardID, listID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.requireList(listID); err != nil {
+		return err
+	}
+	f, err := d.find(cardID)
+	if err != nil {
+		return err
+	}
+	if filepath.Base(filepath.Dir(f.path)) == listID {
+		return nil
+	}
+	dest, err := freePath(filepath.Join(d.root, listID), filepath.Base(f.path))
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.path, dest)
+}
+
+func (d *Dir) UpdateCard(ctx context.Context, cardID string, fields board.CardUpdate) error {
+	if fields.Empty() {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f, err := d.find(cardID)
+	if err != nil {
+		return err
+	}
+	if fields.Name != nil {
+		f.meta.set("title", *fields.Name)
+	}
+	if fields.Desc != nil {
+		f.body = strings.TrimSpace(*fields.Desc)
+	}
+	f.meta.set("updated", stamp())
+	return f.write()
+}
+
+func (d *Dir) AddComment(ctx context.Context, cardID, text string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f, err := d.find(cardID)
+	if err != nil {
+		return err
+	}
+	f.comments = append(f.comments, comment{when: stamp(), text: strings.TrimSpace(text)})
+	return f.write()
+}
+
+func (d *Dir) ArchiveCard(ctx context.Context, cardID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f, err := d.find(cardID)
+	if err != nil {
+		return err
+	}
+	list := filepath.Base(filepath.Dir(f.path))
+	destDir := filepath.Join(d.root, archiveDir, list)
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return err
+	}
+	dest, err := freePath(destDir, filepath.Base(f.path))
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.path, dest)
+}
+
+func (d *Dir) CreateCard(ctx context.Context, listID, name string) (*board.Card, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.requireList(listID); err != nil {
+		return nil, err
+	}
+	path, err := freePath(filepath.Join(d.root, listID), slugify(name)+".md")
+	if err != nil {
+		return nil, err
+	}
+	f := &cardFile{path: path}
+	now := stamp()
+	f.meta.set("id", newID())
+	f.meta.set("title", name)
+	f.meta.set("created", now)
+	f.meta.set("updated", now)
+	if err := f.write(); err != nil {
+		return nil, err
+	}
+	card := f.card(d.root, board.List{ID: listID, Name: listName(listID)})
+	return &card, nil
+}
+
+func (d *Dir) CreateList(ctx context.Context, boardID, name string) (*board.List, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	lists, err := d.lists()
+	if err != nil {
+		return nil, err
+	}
+	next := len(lists) + 1
+	for _, l := range lists {
+		if n, ok := orderPrefix(l.ID); ok && n >= next {
+			next = n + 1
+		}
+	}
+	id := fmt.Sprintf("%02d-%s", next, dirName(name))
+	dir := filepath.Join(d.root, id)
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, keepFile), nil, 0o644); err != nil {
+		return nil, err
+	}
+	return &board.List{ID: id, Name: listName(id)}, nil
+}
+
+func (d *Dir) ArchiveList(ctx context.Context, listID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.requireList(listID); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(d.root, archiveDir), 0o755); err != nil {
+		return err
+	}
+	dest, err := freePath(filepath.Join(d.root, archiveDir), listID)
+	if err != nil {
+		return err
+	}
+	return os.Rename(filepath.Join(d.root, listID), dest)
+}
+
+// RestoreCard moves a card out of .archive/ back into the list it was
+// archived from.
+func (d *Dir) RestoreCard(ctx context.Context, cardID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entries, err := os.ReadDir(filepath.Join(d.root, archiveDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("archived card %s not found", cardID)
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		files, err := d.cardFiles(filepath.Join(archiveDir, e.Name()))
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if f.id() != cardID {
+				continue
+			}
+			if err := d.requireList(e.Name()); err != nil {
+				return fmt.Errorf("restore list %s first: %w", e.Name(), err)
+			}
+			dest, err := freePath(filepath.Join(d.root, e.Name()), filepath.Base(f.path))
+			if err != nil {
+				return err
+			}
+			return os.Rename(f.path, dest)
+		}
+	}
+	return fmt.Errorf("archived card %s not found", cardID)
+}
+
+// RestoreList moves the most recently archived copy of a list back.
+func (d *Dir) RestoreList(ctx context.Context, listID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if listID == "" || strings.ContainsAny(listID, `/\`) || strings.HasPrefix(listID, ".") {
+		return fmt.Errorf("invalid list id %q", listID)
+	}
+	if _, err := os.Stat(filepath.Join(d.root, listID)); err == nil {
+		return fmt.Errorf("list %s already exists", listID)
+	}
+	entries, err := os.ReadDir(filepath.Join(d.root, archiveDir))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	// freePath names repeat archives "<id>-2", "<id>-3"...; the highest is newest
+	src, best := "", 0
+	for _, e := range entries {
+		n := 0
+		if e.Name() == listID {
+			n = 1
+		} else if suffix, ok := strings.CutPrefix(e.Name(), listID+"-"); ok {
+			n, _ = strconv.Atoi(suffix)
+		}
+		if e.IsDir() && n > best {
+			src, best = e.Name(), n
+		}
+	}
+	if src == "" {
+		return fmt.Errorf("archived list %s not found", listID)
+	}
+	return os.Rename(filepath.Join(d.root, archiveDir, src), filepath.Join(d.root, listID))
+}
+
+func (d *Dir) requireList(listID string) error {
+	if listID == "" || strings.ContainsAny(listID, `/\`) || strings.HasPrefix(listID, ".") {
+		return fmt.Errorf("invalid list id %q", listID)
+	}
+	info, err := os.Stat(filepath.Join(d.root, listID))
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("list %s not found", listID)
+	}
+	return nil
+}
+
+// cardFiles parses every card in a list, ordered by creation time.
+func (d *Dir) cardFiles(listID string) ([]*cardFile, error) {
+	entries, err := os.ReadDir(filepath.Join(d.root, listID))
+	if err != nil {
+		return nil, err
+	}
+	var files []*cardFile
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".md" {
+			continue
+		}
+		f, err := readCard(filepath.Join(d.root, listID, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		ci, cj := files[i].meta.get("created"), files[j].meta.get("created")
+		if ci != cj {
+			return ci < cj
+		}
+		return files[i].path < files[j].path
+	})
+	return files, nil
+}
+
+func (d *Dir) find(cardID string) (*cardFile, error) {
+	lists, err := d.lists()
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lists {
+		files, err := d.cardFiles(l.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			if f.id() == cardID {
+				return f, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("card %s not found", cardID)
+}
+
+var orderRe = regexp.MustCompile(`^(\d+)[-_ ]`)
+
+func orderPrefix(dir string) (int, bool) {
+	m := orderRe.FindStringSubmatch(dir)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	return n, err == nil
+}
+
+// listName hides the ordering prefix: "02-In-Review" shows as "In Review".
+func listName(dir string) string {
+	name := orderRe.ReplaceAllString(dir, "")
+	name = strings.ReplaceAll(name, "-", " ")
+	if name == "" {
+		return dir
+	}
+	return name
+}
+
+var (
+	slugRe    = regexp.MustCompile(`[^a-z0-9]+`)
+	dirNameRe = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// dirName keeps case so list names survive the round trip through listName.
+func dirName(s string) string {
+	name := strings.Trim(dirNameRe.ReplaceAllString(s, "-"), "-")
+	if name == "" {
+		name = "list"
+	}
+	return name
+}
+
+func slugify(s string) string {
+	slug := strings.Trim(slugRe.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if len(slug) > 60 {
+		slug = strings.TrimRight(slug[:60], "-")
+	}
+	if slug == "" {
+		slug = "untitled"
+	}
+	return slug
+}
+
+// freePath returns dir/name, adding a -2, -3... suffix if that is taken.
+func freePath(dir, name string) (string, error) {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 1; i < 1000; i++ {
+		candidate := name
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d%s", base, i, ext)
+		}
+		path := filepath.Join(dir, candidate)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no free file name for %s in %s", name, dir)
+}
+
+func newID() string {
+	var b [6]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func stamp() string {
+	return time.Now().UTC().Format("2006-01-02 15:04 MST")
+}
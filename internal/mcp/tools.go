@@ -0,0 +1,371 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+type toolArgs struct {
+	BoardID string  `json:"board_id"`
+	CardID  string  `json:"card_id"`
+	ListID  string  `json:"list_id"`
+	Name    *string `json:"name"`
+	Desc    *string `json:"desc"`
+	Text    string  `json:"text"`
+	Label   string  `json:"label"`
+}
+
+type tool struct {
+	name        string
+	description string
+	props       map[string]string // property -> description, all strings
+	required    []string
+	run         func(context.Context, *Server, toolArgs) (string, error)
+}
+
+var tools = []tool{
+	{
+		name:        "list_boards",
+		description: "List the open boards the user can access.",
+		run: func(ctx context.Context, s *Server, _ toolArgs) (string, error) {
+			boards, err := s.provider.Boards(ctx)
+			if err != nil {
+				return "", err
+			}
+			return jsonText(boards)
+		},
+	},
+	{
+		name:        "get_board",
+		description: "Get a board's lists with the id and name of every card in them.",
+		props:       map[string]string{"board_id": "board id; defaults to the configured board"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			boardID, err := s.resolveBoard(a.BoardID)
+			if err != nil {
+				return "", err
+			}
+			lists, err := s.provider.Lists(ctx, boardID)
+			if err != nil {
+				return "", err
+			}
+			cards, err := s.provider.Cards(ctx, boardID)
+			if err != nil {
+				return "", err
+			}
+
+			type cardRef struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			}
+			type listView struct {
+				ID    string    `json:"id"`
+				Name  string    `json:"name"`
+				Cards []cardRef `json:"cards"`
+			}
+			views := make([]listView, 0, len(lists))
+			index := make(map[string]int, len(lists))
+			for i, l := range lists {
+				index[l.ID] = i
+				views = append(views, listView{ID: l.ID, Name: l.Name, Cards: []cardRef{}})
+			}
+			for _, c := range cards {
+				if i, ok := index[c.IDList]; ok {
+					views[i].Cards = append(views[i].Cards, cardRef{ID: c.ID, Name: c.Name})
+				}
+			}
+			return jsonText(map[string]any{"board_id": boardID, "lists": views})
+		},
+	},
+	{
+		name:        "get_card",
+		description: "Get a card's full details: name, description, list, url, labels and due date.",
+		props: map[string]string{
+			"card_id":  "card id",
+			"board_id": "board the card is on; defaults to the configured board",
+		},
+		required: []string{"card_id"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			boardID, err := s.resolveBoard(a.BoardID)
+			if err != nil {
+				return "", err
+			}
+			cards, err := s.provider.Cards(ctx, boardID)
+			if err != nil {
+				return "", err
+			}
+			for _, c := range cards {
+				if c.ID == a.CardID {
+					labels := c.Labels
+					if labels == nil {
+						labels = []board.Label{}
+					}
+					return jsonText(map[string]any{
+						"id":           c.ID,
+						"name":         c.Name,
+						"desc":         c.Desc,
+						"list_id":      c.IDList,
+						"list_name":    c.ListName,
+						"url":          c.URL,
+						"labels":       labels,
+						"member_ids":   c.MemberIDs,
+						"due":          c.Due,
+						"start":        c.Start,
+						"due_complete": c.DueComplete,
+						"check_items":  fmt.Sprintf("%d/%d", c.CheckItemsChecked, c.CheckItems),
+					})
+				}
+			}
+			return "", fmt.Errorf("card %s not found on board %s", a.CardID, boardID)
+		},
+	},
+	{
+		name:        "move_card",
+		description: "Move a card to another list.",
+		props:       map[string]string{"card_id": "card id", "list_id": "destination list id"},
+		required:    []string{"card_id", "list_id"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			if err := s.provider.MoveCard(ctx, a.CardID, a.ListID); err != nil {
+				return "", err
+			}
+			return "moved " + a.CardID, nil
+		},
+	},
+	{
+		name:        "update_card",
+		description: "Rename a card and/or replace its description. Omitted fields are left unchanged.",
+		props: map[string]string{
+			"card_id": "card id",
+			"name":    "new card name",
+			"desc":    "new description (replaces the old one)",
+		},
+		required: []string{"card_id"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			fields := board.CardUpdate{Name: a.Name, Desc: a.Desc}
+			if fields.Empty() {
+				return "", errors.New("nothing to update: pass name and/or desc")
+			}
+			if err := s.provider.UpdateCard(ctx, a.CardID, fields); err != nil {
+				return "", err
+			}
+			return "updated " + a.CardID, nil
+		},
+	},
+	{
+		name:        "add_comment",
+		description: "Add a comment to a card.",
+		props:       map[string]string{"card_id": "card id", "text": "comment text"},
+		required:    []string{"card_id", "text"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			if err := s.provider.AddComment(ctx, a.CardID, a.Text); err != nil {
+				return "", err
+			}
+			return "commented on " + a.CardID, nil
+		},
+	},
+	{
+		name:        "create_card",
+		description: "Create a card at the bottom of a list.",
+		props:       map[string]string{"list_id": "list id", "name": "card name"},
+		required:    []string{"list_id", "name"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			if a.Name == nil || *a.Name == "" {
+				return "", errors.New("name is required")
+			}
+			card, err := s.provider.CreateCard(ctx, a.ListID, *a.Name)
+			if err != nil {
+				return "", err
+			}
+			return jsonText(map[string]string{"id": card.ID, "name": card.Name, "list_id": card.IDList})
+		},
+	},
+	{
+		name:        "archive_card",
+		description: "Archive a card.",
+		props:       map[string]string{"card_id": "card id"},
+		required:    []string{"card_id"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			if err := s.provider.ArchiveCard(ctx, a.CardID); err != nil {
+				return "", err
+			}
+			return "archived " + a.CardID, nil
+		},
+	},
+	{
+		name:        "create_list",
+		description: "Create a list on a board.",
+		props: map[string]string{
+			"name":     "list name",
+			"board_id": "board id; defaults to the configured board",
+		},
+		required: []string{"name"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			boardID, err := s.resolveBoard(a.BoardID)
+			if err != nil {
+				return "", err
+			}
+			if a.Name == nil || *a.Name == "" {
+				return "", errors.New("name is required")
+			}
+			list, err := s.provider.CreateList(ctx, boardID, *a.Name)
+			if err != nil {
+				return "", err
+			}
+			return jsonText(list)
+		},
+	},
+	{
+		name:        "archive_list",
+		description: "Archive a list and hide its cards.",
+		props:       map[string]string{"list_id": "list id"},
+		required:    []string{"list_id"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			if err := s.provider.ArchiveList(ctx, a.ListID); err != nil {
+				return "", err
+			}
+			return "archived list " + a.ListID, nil
+		},
+	},
+	{
+		name:        "list_labels",
+		description: "List the labels defined on a board.",
+		props:       map[string]string{"board_id": "board id; defaults to the configured board"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			l, err := s.labeler()
+			if err != nil {
+				return "", err
+			}
+			boardID, err := s.resolveBoard(a.BoardID)
+			if err != nil {
+				return "", err
+			}
+			labels, err := l.Labels(ctx, boardID)
+			if err != nil {
+				return "", err
+			}
+			return jsonText(labels)
+		},
+	},
+	{
+		name:        "add_label",
+		description: "Add a board label to a card.",
+		props: map[string]string{
+			"card_id":  "card id",
+			"label":    "label id or name",
+			"board_id": "board the card is on; defaults to the configured board",
+		},
+		required: []string{"card_id", "label"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			return s.setLabel(ctx, a, true)
+		},
+	},
+	{
+		name:        "remove_label",
+		description: "Remove a label from a card.",
+		props: map[string]string{
+			"card_id":  "card id",
+			"label":    "label id or name",
+			"board_id": "board the card is on; defaults to the configured board",
+		},
+		required: []string{"card_id", "label"},
+		run: func(ctx context.Context, s *Server, a toolArgs) (string, error) {
+			return s.setLabel(ctx, a, false)
+		},
+	},
+}
+
+func toolByName(name string) (tool, bool) {
+	for _, t := range tools {
+		if t.name == name {
+			return t, true
+		}
+	}
+	return tool{}, false
+}
+
+func toolDefs() []map[string]any {
+	defs := make([]map[string]any, 0, len(tools))
+	for _, t := range tools {
+		props := make(map[string]any, len(t.props))
+		for name, desc := range t.props {
+			props[name] = map[string]string{"type": "string", "description": desc}
+		}
+		schema := map[string]any{"type": "object", "properties": props}
+		if len(t.required) > 0 {
+			schema["required"] = t.required
+		}
+		defs = append(defs, map[string]any{
+			"name":        t.name,
+			"description": t.description,
+			"inputSchema": schema,
+		})
+	}
+	return defs
+}
+
+func (s *Server) resolveBoard(boardID string) (string, error) {
+	if boardID != "" {
+		return boardID, nil
+	}
+	if s.boardID != "" {
+		return s.boardID, nil
+	}
+	return "", errors.New("board_id is required (no default board configured)")
+}
+
+func (s *Server) labeler() (board.Labeler, error) {
+	l, ok := s.provider.(board.Labeler)
+	if !ok {
+		return nil, fmt.Errorf("%s labels: %w", s.provider.Name(), board.ErrUnsupported)
+	}
+	return l, nil
+}
+
+// setLabel resolves a label by id or name on the card's board and adds or
+// removes it.
+func (s *Server) setLabel(ctx context.Context, a toolArgs, add bool) (string, error) {
+	l, err := s.labeler()
+	if err != nil {
+		return "", err
+	}
+	boardID, err := s.resolveBoard(a.BoardID)
+	if err != nil {
+		return "", err
+	}
+	labels, err := l.Labels(ctx, boardID)
+	if err != nil {
+		return "", err
+	}
+	var label *board.Label
+	for i := range labels {
+		if labels[i].ID == a.Label || strings.EqualFold(labels[i].Name, a.Label) {
+			label = &labels[i]
+			break
+		}
+	}
+	if label == nil {
+		return "", fmt.Errorf("no label %q on board %s", a.Label, boardID)
+	}
+	if add {
+		if err := l.AddLabel(ctx, a.CardID, label.ID); err != nil {
+			return "", err
+		}
+		return "labelled " + a.CardID, nil
+	}
+	if err := l.RemoveLabel(ctx, a.CardID, label.ID); err != nil {
+		return "", err
+	}
+	return "unlabelled " + a.CardID, nil
+}
+
+func jsonText(v any) (string, error) {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
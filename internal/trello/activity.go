@@ -0,0 +1,131 @@
+package trello
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+var _ board.ActivityReader = (*Client)(nil)
+
+// activityLimit is how many of a card's most recent actions are fetched.
+const activityLimit = 50
+
+type cardActionResponse struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	Date          time.Time `json:"date"`
+	MemberCreator struct {
+		FullName string `json:"fullName"`
+		Username string `json:"username"`
+	} `json:"memberCreator"`
+	Data struct {
+		Text string `json:"text"`
+		Card struct {
+			Closed      bool `json:"closed"`
+			DueComplete bool `json:"dueComplete"`
+		} `json:"card"`
+		ListBefore *struct {
+			Name string `json:"name"`
+		} `json:"listBefore"`
+		ListAfter *struct {
+			Name string `json:"name"`
+		} `json:"listAfter"`
+		// old holds the previous value of each field the update changed
+		Old map[string]json.RawMessage `json:"old"`
+	} `json:"data"`
+}
+
+func (c *Client) Activity(ctx context.Context, cardID string) ([]board.Activity, error) {
+	if !c.CanAuth() {
+		return nil, errors.New("missing TRELLO_API_KEY or TRELLO_API_TOKEN")
+	}
+	u, err := url.Parse(baseURL + "/cards/" + cardID + "/actions")
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("key", c.apiKey)
+	q.Set("token", c.token)
+	q.Set("filter", "commentCard,updateCard,createCard")
+	q.Set("fields", "id,type,date,data")
+	q.Set("memberCreator_fields", "fullName,username")
+	q.Set("limit", strconv.Itoa(activityLimit))
+	u.RawQuery = q.Encode()
+
+	var raw []cardActionResponse
+	if err := c.getJSON(ctx, u.String(), &raw); err != nil {
+		return nil, err
+	}
+	activity := make([]board.Activity, 0, len(raw))
+	for _, ra := range raw {
+		activity = append(activity, ra.toActivity())
+	}
+	// trello lists newest first
+	slices.Reverse(activity)
+	return activity, nil
+}
+
+func (ra cardActionResponse) toActivity() board.Activity {
+	a := board.Activity{
+		ID:     ra.ID,
+		Kind:   "update",
+		Author: ra.MemberCreator.FullName,
+		At:     ra.Date,
+	}
+	if a.Author == "" {
+		a.Author = ra.MemberCreator.Username
+	}
+	switch ra.Type {
+	case "commentCard":
+		a.Kind = "comment"
+		a.Text = ra.Data.Text
+	case "createCard":
+		a.Text = "created the card"
+	default:
+		a.Text = ra.describeUpdate()
+	}
+	return a
+}
+
+// describeUpdate says what an updateCard action changed, from the fields
+// trello reports the old values of.
+func (ra cardActionResponse) describeUpdate() string {
+	d := ra.Data
+	if d.ListBefore != nil && d.ListAfter != nil {
+		return fmt.Sprintf("moved it from %s to %s", d.ListBefore.Name, d.ListAfter.Name)
+	}
+	if raw, ok := d.Old["name"]; ok {
+		var name string
+		if json.Unmarshal(raw, &name) == nil {
+			return fmt.Sprintf("renamed it from %q", name)
+		}
+	}
+	switch {
+	case d.Old["desc"] != nil:
+		return "edited the description"
+	case d.Old["closed"] != nil && d.Card.Closed:
+		return "archived it"
+	case d.Old["closed"] != nil:
+		return "restored it"
+	case d.Old["dueComplete"] != nil && d.Card.DueComplete:
+		return "marked it done"
+	case d.Old["dueComplete"] != nil:
+		return "marked it not done"
+	case d.Old["due"] != nil:
+		return "changed the due date"
+	case d.Old["start"] != nil:
+		return "changed the start date"
+	case d.Old["pos"] != nil:
+		return "reordered it"
+	default:
+		return "updated it"
+	}
+}
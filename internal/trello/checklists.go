@@ -0,0 +1,100 @@
+package trello
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+var _ board.Checklister = (*Client)(nil)
+
+type checklistResponse struct {
+	ID         string              `json:"id"`
+	Name       string              `json:"name"`
+	Pos        float64             `json:"pos"`
+	CheckItems []checkItemResponse `json:"checkItems"`
+}
+
+type checkItemResponse struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	State string  `json:"state"` // complete or incomplete
+	Pos   float64 `json:"pos"`
+}
+
+func (ri checkItemResponse) toItem() board.CheckItem {
+	return board.CheckItem{ID: ri.ID, Name: ri.Name, Checked: ri.State == "complete"}
+}
+
+func (rc checklistResponse) toChecklist() board.Checklist {
+	// items come back in no promised order; trello shows them by pos
+	slices.SortStableFunc(rc.CheckItems, func(a, b checkItemResponse) int { return cmp.Compare(a.Pos, b.Pos) })
+	items := make([]board.CheckItem, 0, len(rc.CheckItems))
+	for _, ri := range rc.CheckItems {
+		items = append(items, ri.toItem())
+	}
+	return board.Checklist{ID: rc.ID, Name: rc.Name, Items: items}
+}
+
+func (c *Client) Checklists(ctx context.Context, cardID string) ([]board.Checklist, error) {
+	if !c.CanAuth() {
+		return nil, errors.New("missing TRELLO_API_KEY or TRELLO_API_TOKEN")
+	}
+	u, err := url.Parse(baseURL + "/cards/" + cardID + "/checklists")
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("key", c.apiKey)
+	q.Set("token", c.token)
+	q.Set("fields", "id,name,pos")
+	q.Set("checkItems", "all")
+	q.Set("checkItem_fields", "id,name,state,pos")
+	u.RawQuery = q.Encode()
+
+	var raw []checklistResponse
+	if err := c.getJSON(ctx, u.String(), &raw); err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(raw, func(a, b checklistResponse) int { return cmp.Compare(a.Pos, b.Pos) })
+	lists := make([]board.Checklist, 0, len(raw))
+	for _, rc := range raw {
+		lists = append(lists, rc.toChecklist())
+	}
+	return lists, nil
+}
+
+func (c *Client) CreateChecklist(ctx context.Context, cardID, name string) (*board.Checklist, error) {
+	var rc checklistResponse
+	if err := c.postForm(ctx, "/cards/"+cardID+"/checklists", url.Values{"name": {name}}, &rc); err != nil {
+		return nil, err
+	}
+	list := rc.toChecklist()
+	return &list, nil
+}
+
+func (c *Client) AddCheckItem(ctx context.Context, checklistID, name string) (*board.CheckItem, error) {
+	var ri checkItemResponse
+	vals := url.Values{"name": {name}, "pos": {"bottom"}}
+	if err := c.postForm(ctx, "/checklists/"+checklistID+"/checkItems", vals, &ri); err != nil {
+		return nil, err
+	}
+	item := ri.toItem()
+	return &item, nil
+}
+
+func (c *Client) SetCheckItem(ctx context.Context, cardID, itemID string, checked bool) error {
+	state := "incomplete"
+	if checked {
+		state = "complete"
+	}
+	return c.putForm(ctx, "/cards/"+cardID+"/checkItem/"+itemID, url.Values{"state": {state}})
+}
+
+func (c *Client) DeleteCheckItem(ctx context.Context, cardID, itemID string) error {
+	return c.deleteReq(ctx, "/cards/"+cardID+"/checkItem/"+itemID)
+}
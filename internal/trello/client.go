@@ -8,44 +8,87 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/codywilliamson/aboard/internal/board"
 )
 
 const baseURL = "https://api.trello.com/1"
 
+const cardFields = "id,name,desc,idList,url,shortUrl,labels,idMembers,due,dueComplete,start,badges"
+
+var errNotFound = errors.New("not found")
+
 type Client struct {
 	apiKey string
 	token  string
 	http   *http.Client
-}
 
-type Card struct {
-	ID       string
-	Name     string
-	Desc     string
-	URL      string
-	ShortURL string
-	IDList   string
-	ListName string
+	// list names per board from the last Lists call, so Cards and Changes
+	// can label cards without fetching the lists again
+	mu        sync.Mutex
+	listNames map[string]map[string]string
+	// the signed-in member, see Me
+	me *board.Member
+	// full board ids by the id or short link they were asked for with
+	boardIDs map[string]string
+
+	// webhook receiver, see EnableWebhook
+	hookAddr   string
+	hookURL    string
+	hookSecret string
+	hookServer *http.Server // shared by every watch, up until Close
+	hooks      *webhookHandler
+	watchers   map[string]context.CancelFunc // by webhook id
+	watches    sync.WaitGroup
 }
 
-type Board struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-}
+var _ board.BoardProvider = (*Client)(nil)
 
-type List struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-}
+var _ board.CommentDeleter = (*Client)(nil)
 
 type cardResponse struct {
 	ID       string `json:"id"`
 	IDList   string `json:"idList"`
+	IDBoard  string `json:"idBoard"`
 	Name     string `json:"name"`
 	Desc     string `json:"desc"`
 	URL      string `json:"url"`
 	ShortURL string `json:"shortUrl"`
+	Closed   bool   `json:"closed"`
+
+	Labels      []board.Label `json:"labels"`
+	IDMembers   []string      `json:"idMembers"`
+	Due         *time.Time    `json:"due"`
+	Start       *time.Time    `json:"start"`
+	DueComplete bool          `json:"dueComplete"`
+	Badges      struct {
+		CheckItems        int `json:"checkItems"`
+		CheckItemsChecked int `json:"checkItemsChecked"`
+	} `json:"badges"`
+}
+
+func (rc cardResponse) toCard(listName string) board.Card {
+	return board.Card{
+		ID:       rc.ID,
+		IDList:   rc.IDList,
+		Name:     rc.Name,
+		Desc:     rc.Desc,
+		URL:      rc.URL,
+		ShortURL: rc.ShortURL,
+		ListName: listName,
+		Labels:   rc.Labels,
+
+		MemberIDs: rc.IDMembers,
+
+		Due:         rc.Due,
+		Start:       rc.Start,
+		DueComplete: rc.DueComplete,
+
+		CheckItems:        rc.Badges.CheckItems,
+		CheckItemsChecked: rc.Badges.CheckItemsChecked,
+	}
 }
 
 func NewClient(apiKey, token string) *Client {
@@ -55,14 +98,30 @@ func NewClient(apiKey, token string) *Client {
 		http: &http.Client{
 			Timeout: 12 * time.Second,
 		},
+		listNames: make(map[string]map[string]string),
+		boardIDs:  make(map[string]string),
+		watchers:  make(map[string]context.CancelFunc),
 	}
 }
 
+func (c *Client) Name() string {
+	return "trello"
+}
+
 func (c *Client) CanAuth() bool {
 	return c.apiKey != "" && c.token != ""
 }
 
-func (c *Client) Boards(ctx context.Context) ([]Board, error) {
+func (c *Client) AuthHelp() []string {
+	return []string{
+		"set these env vars:",
+		"  TRELLO_API_KEY",
+		"  TRELLO_API_TOKEN",
+		"  TRELLO_BOARD_ID (optional)",
+	}
+}
+
+func (c *Client) Boards(ctx context.Context) ([]board.Board, error) {
 	if !c.CanAuth() {
 		return nil, errors.New("missing TRELLO_API_KEY or TRELLO_API_TOKEN")
 	}
@@ -78,14 +137,14 @@ func (c *Client) Boards(ctx context.Context) ([]Board, error) {
 	q.Set("filter", "open")
 	u.RawQuery = q.Encode()
 
-	var boards []Board
+	var boards []board.Board
 	if err := c.getJSON(ctx, u.String(), &boards); err != nil {
 		return nil, err
 	}
 	return boards, nil
 }
 
-func (c *Client) CardsForBoard(ctx context.Context, boardID string) ([]Card, error) {
+func (c *Client) Cards(ctx context.Context, boardID string) ([]board.Card, error) {
 	if boardID == "" {
 		return nil, errors.New("board id is required")
 	}
@@ -93,13 +152,13 @@ func (c *Client) CardsForBoard(ctx context.Context, boardID string) ([]Card, err
 		return nil, errors.New("missing TRELLO_API_KEY or TRELLO_API_TOKEN")
 	}
 
-	lists, err := c.ListsForBoard(ctx, boardID)
-	if err != nil {
-		return nil, err
-	}
-	listByID := make(map[string]string, len(lists))
-	for _, l := range lists {
-		listByID[l.ID] = l.Name
+	c.mu.Lock()
+	_, cached := c.listNames[boardID]
+	c.mu.Unlock()
+	if !cached {
+		if _, err := c.Lists(ctx, boardID); err != nil {
+			return nil, err
+		}
 	}
 
 	u, err := url.Parse(baseURL + "/boards/" + boardID + "/cards")
@@ -109,7 +168,7 @@ func (c *Client) CardsForBoard(ctx context.Context, boardID string) ([]Card, err
 	q := u.Query()
 	q.Set("key", c.apiKey)
 	q.Set("token", c.token)
-	q.Set("fields", "id,name,desc,idList,url,shortUrl")
+	q.Set("fields", cardFields)
 	q.Set("filter", "open")
 	u.RawQuery = q.Encode()
 
@@ -118,23 +177,15 @@ func (c *Client) CardsForBoard(ctx context.Context, boardID string) ([]Card, err
 		return nil, err
 	}
 
-	cards := make([]Card, 0, len(rawCards))
+	cards := make([]board.Card, 0, len(rawCards))
 	for _, rc := range rawCards {
-		cards = append(cards, Card{
-			ID:       rc.ID,
-			IDList:   rc.IDList,
-			Name:     rc.Name,
-			Desc:     rc.Desc,
-			URL:      rc.URL,
-			ShortURL: rc.ShortURL,
-			ListName: listByID[rc.IDList],
-		})
+		cards = append(cards, rc.toCard(c.listName(boardID, rc.IDList)))
 	}
 
 	return cards, nil
 }
 
-func (c *Client) ListsForBoard(ctx context.Context, boardID string) ([]List, error) {
+func (c *Client) Lists(ctx context.Context, boardID string) ([]board.List, error) {
 	u, err := url.Parse(baseURL + "/boards/" + boardID + "/lists")
 	if err != nil {
 		return nil, err
@@ -146,47 +197,111 @@ func (c *Client) ListsForBoard(ctx context.Context, boardID string) ([]List, err
 	q.Set("filter", "open")
 	u.RawQuery = q.Encode()
 
-	var lists []List
+	var lists []board.List
 	if err := c.getJSON(ctx, u.String(), &lists); err != nil {
 		return nil, err
 	}
+
+	names := make(map[string]string, len(lists))
+	for _, l := range lists {
+		names[l.ID] = l.Name
+	}
+	c.mu.Lock()
+	c.listNames[boardID] = names
+	c.mu.Unlock()
 	return lists, nil
 }
 
+func (c *Client) listName(boardID, listID string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.listNames[boardID][listID]
+}
+
+// fullBoardID resolves a board short link (what TRELLO_BOARD_ID and board
+// urls usually hold) to the board's id, which is what cards and webhook
+// payloads carry. it's fetched once per board.
+func (c *Client) fullBoardID(ctx context.Context, boardID string) (string, error) {
+	c.mu.Lock()
+	id, ok := c.boardIDs[boardID]
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	u, err := url.Parse(baseURL + "/boards/" + boardID)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("key", c.apiKey)
+	q.Set("token", c.token)
+	q.Set("fields", "id")
+	u.RawQuery = q.Encode()
+
+	var raw struct {
+		ID string `json:"id"`
+	}
+	if err := c.getJSON(ctx, u.String(), &raw); err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.boardIDs[boardID] = raw.ID
+	c.mu.Unlock()
+	return raw.ID, nil
+}
+
 func (c *Client) MoveCard(ctx context.Context, cardID, listID string) error {
 	return c.putForm(ctx, "/cards/"+cardID, url.Values{"idList": {listID}})
 }
 
-func (c *Client) UpdateCard(ctx context.Context, cardID, name, desc string) error {
+func (c *Client) UpdateCard(ctx context.Context, cardID string, fields board.CardUpdate) error {
 	vals := url.Values{}
-	if name != "" {
-		vals.Set("name", name)
+	if fields.Name != nil {
+		vals.Set("name", *fields.Name)
 	}
-	if desc != "" {
-		vals.Set("desc", desc)
+	if fields.Desc != nil {
+		vals.Set("desc", *fields.Desc)
 	}
 	return c.putForm(ctx, "/cards/"+cardID, vals)
 }
 
 func (c *Client) AddComment(ctx context.Context, cardID, text string) error {
-	return c.postForm(ctx, "/cards/"+cardID+"/actions/comments", url.Values{"text": {text}}, nil)
+	_, err := c.PostComment(ctx, cardID, text)
+	return err
+}
+
+// PostComment returns the id of the commentCard action trello creates.
+func (c *Client) PostComment(ctx context.Context, cardID, text string) (string, error) {
+	var action struct {
+		ID string `json:"id"`
+	}
+	if err := c.postForm(ctx, "/cards/"+cardID+"/actions/comments", url.Values{"text": {text}}, &action); err != nil {
+		return "", err
+	}
+	return action.ID, nil
+}
+
+// DeleteComment deletes the comment action; trello only allows its author to.
+func (c *Client) DeleteComment(ctx context.Context, cardID, commentID string) error {
+	return c.deleteReq(ctx, "/actions/"+commentID)
 }
 
 func (c *Client) ArchiveCard(ctx context.Context, cardID string) error {
 	return c.putForm(ctx, "/cards/"+cardID, url.Values{"closed": {"true"}})
 }
 
-func (c *Client) CreateCard(ctx context.Context, listID, name string) (*Card, error) {
+func (c *Client) CreateCard(ctx context.Context, listID, name string) (*board.Card, error) {
 	var raw cardResponse
 	err := c.postForm(ctx, "/lists/"+listID+"/cards", url.Values{"name": {name}}, &raw)
 	if err != nil {
 		return nil, err
 	}
-	return &Card{ID: raw.ID, Name: raw.Name, IDList: raw.IDList, URL: raw.URL, ShortURL: raw.ShortURL}, nil
+	return &board.Card{ID: raw.ID, Name: raw.Name, IDList: raw.IDList, URL: raw.URL, ShortURL: raw.ShortURL}, nil
 }
 
-func (c *Client) CreateList(ctx context.Context, boardID, name string) (*List, error) {
-	var raw List
+func (c *Client) CreateList(ctx context.Context, boardID, name string) (*board.List, error) {
+	var raw board.List
 	err := c.postForm(ctx, "/boards/"+boardID+"/lists", url.Values{"name": {name}}, &raw)
 	if err != nil {
 		return nil, err
@@ -198,6 +313,14 @@ func (c *Client) ArchiveList(ctx context.Context, listID string) error {
 	return c.putForm(ctx, "/lists/"+listID+"/closed", url.Values{"value": {"true"}})
 }
 
+func (c *Client) RestoreCard(ctx context.Context, cardID string) error {
+	return c.putForm(ctx, "/cards/"+cardID, url.Values{"closed": {"false"}})
+}
+
+func (c *Client) RestoreList(ctx context.Context, listID string) error {
+	return c.putForm(ctx, "/lists/"+listID+"/closed", url.Values{"value": {"false"}})
+}
+
 func (c *Client) getJSON(ctx context.Context, endpoint string, target any) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
@@ -210,6 +333,9 @@ func (c *Client) getJSON(ctx context.Context, endpoint string, target any) error
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("trello returned %s: %w", resp.Status, errNotFound)
+	}
 	if resp.StatusCode >= 300 {
 		return fmt.Errorf("trello returned %s", resp.Status)
 	}
@@ -260,3 +386,20 @@ func (c *Client) postForm(ctx context.Context, path string, vals url.Values, tar
 	}
 	return nil
 }
+
+func (c *Client) deleteReq(ctx context.Context, path string) error {
+	vals := url.Values{"key": {c.apiKey}, "token": {c.token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, baseURL+path+"?"+vals.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("trello returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package trello
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+var _ board.DueDater = (*Client)(nil)
+
+func (c *Client) SetDue(ctx context.Context, cardID string, due *time.Time) error {
+	value := "null"
+	if due != nil {
+		value = due.UTC().Format(time.RFC3339)
+	}
+	return c.putForm(ctx, "/cards/"+cardID, url.Values{"due": {value}})
+}
+
+func (c *Client) SetDueComplete(ctx context.Context, cardID string, complete bool) error {
+	return c.putForm(ctx, "/cards/"+cardID, url.Values{"dueComplete": {strconv.FormatBool(complete)}})
+}
@@ -0,0 +1,41 @@
+package trello
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+var _ board.Labeler = (*Client)(nil)
+
+func (c *Client) Labels(ctx context.Context, boardID string) ([]board.Label, error) {
+	if !c.CanAuth() {
+		return nil, errors.New("missing TRELLO_API_KEY or TRELLO_API_TOKEN")
+	}
+	u, err := url.Parse(baseURL + "/boards/" + boardID + "/labels")
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("key", c.apiKey)
+	q.Set("token", c.token)
+	q.Set("fields", "id,name,color")
+	q.Set("limit", "1000")
+	u.RawQuery = q.Encode()
+
+	var labels []board.Label
+	if err := c.getJSON(ctx, u.String(), &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (c *Client) AddLabel(ctx context.Context, cardID, labelID string) error {
+	return c.postForm(ctx, "/cards/"+cardID+"/idLabels", url.Values{"value": {labelID}}, nil)
+}
+
+func (c *Client) RemoveLabel(ctx context.Context, cardID, labelID string) error {
+	return c.deleteReq(ctx, "/cards/"+cardID+"/idLabels/"+labelID)
+}
@@ -0,0 +1,63 @@
+package trello
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+var _ board.Assigner = (*Client)(nil)
+
+const memberFields = "id,username,fullName"
+
+func (c *Client) Members(ctx context.Context, boardID string) ([]board.Member, error) {
+	var members []board.Member
+	if err := c.getMembers(ctx, "/boards/"+boardID+"/members", &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// Me is fetched once; the token's member doesn't change.
+func (c *Client) Me(ctx context.Context) (*board.Member, error) {
+	c.mu.Lock()
+	me := c.me
+	c.mu.Unlock()
+	if me != nil {
+		return me, nil
+	}
+	me = &board.Member{}
+	if err := c.getMembers(ctx, "/members/me", me); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.me = me
+	c.mu.Unlock()
+	return me, nil
+}
+
+func (c *Client) AddMember(ctx context.Context, cardID, memberID string) error {
+	return c.postForm(ctx, "/cards/"+cardID+"/idMembers", url.Values{"value": {memberID}}, nil)
+}
+
+func (c *Client) RemoveMember(ctx context.Context, cardID, memberID string) error {
+	return c.deleteReq(ctx, "/cards/"+cardID+"/idMembers/"+memberID)
+}
+
+func (c *Client) getMembers(ctx context.Context, path string, target any) error {
+	if !c.CanAuth() {
+		return errors.New("missing TRELLO_API_KEY or TRELLO_API_TOKEN")
+	}
+	u, err := url.Parse(baseURL + path)
+	if err != nil {
+		return err
+	}
+	q := u.Query()
+	q.Set("key", c.apiKey)
+	q.Set("token", c.token)
+	q.Set("fields", memberFields)
+	u.RawQuery = q.Encode()
+	return c.getJSON(ctx, u.String(), target)
+}
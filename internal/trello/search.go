@@ -0,0 +1,70 @@
+package trello
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+var _ board.Searcher = (*Client)(nil)
+
+// searchLimit caps the cards one search returns; boards are fewer.
+const (
+	searchLimit      = 50
+	searchBoardLimit = 10
+)
+
+type searchResponse struct {
+	Boards []board.Board `json:"boards"`
+	Cards  []struct {
+		cardResponse
+		Board board.Board `json:"board"`
+		List  board.List  `json:"list"`
+	} `json:"cards"`
+}
+
+// Search uses trello's own search, which understands its operators
+// (label:, @member, due:, board:, list:, is:open, ...).
+func (c *Client) Search(ctx context.Context, query string) (*board.SearchResults, error) {
+	if !c.CanAuth() {
+		return nil, errors.New("missing TRELLO_API_KEY or TRELLO_API_TOKEN")
+	}
+	u, err := url.Parse(baseURL + "/search")
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("key", c.apiKey)
+	q.Set("token", c.token)
+	q.Set("query", query)
+	q.Set("modelTypes", "cards,boards")
+	q.Set("partial", "true")
+	q.Set("card_fields", cardFields+",idBoard,closed")
+	q.Set("card_board", "true")
+	q.Set("card_list", "true")
+	q.Set("cards_limit", strconv.Itoa(searchLimit))
+	q.Set("board_fields", "id,name")
+	q.Set("boards_limit", strconv.Itoa(searchBoardLimit))
+	u.RawQuery = q.Encode()
+
+	var raw searchResponse
+	if err := c.getJSON(ctx, u.String(), &raw); err != nil {
+		return nil, err
+	}
+	results := &board.SearchResults{Boards: raw.Boards}
+	for _, rc := range raw.Cards {
+		// archived cards can't be focused on their board
+		if rc.Closed {
+			continue
+		}
+		results.Cards = append(results.Cards, board.FoundCard{
+			Card:      rc.toCard(rc.List.Name),
+			BoardID:   rc.IDBoard,
+			BoardName: rc.Board.Name,
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,215 @@
+package trello
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+var _ board.Syncer = (*Client)(nil)
+
+const (
+	// actionPage is the most board actions trello returns per request; a
+	// full page means we may have missed some, so we reload instead.
+	actionPage = 1000
+	// maxCardFetches bounds the per-card requests a sync may make before a
+	// full reload is cheaper.
+	maxCardFetches = 25
+)
+
+type actionResponse struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Card *struct {
+			ID string `json:"id"`
+		} `json:"card"`
+		List *struct {
+			ID string `json:"id"`
+		} `json:"list"`
+	} `json:"data"`
+}
+
+// Load reads the board with its open lists and cards and its newest action,
+// which is the cursor, in a single request.
+func (c *Client) Load(ctx context.Context, boardID string) (*board.Changes, error) {
+	if !c.CanAuth() {
+		return nil, errors.New("missing TRELLO_API_KEY or TRELLO_API_TOKEN")
+	}
+
+	u, err := url.Parse(baseURL + "/boards/" + boardID)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("key", c.apiKey)
+	q.Set("token", c.token)
+	q.Set("fields", "id")
+	q.Set("lists", "open")
+	q.Set("list_fields", "id,name")
+	q.Set("cards", "open")
+	q.Set("card_fields", cardFields)
+	q.Set("actions", "all")
+	q.Set("actions_limit", "1")
+	q.Set("action_fields", "id")
+	u.RawQuery = q.Encode()
+
+	var raw struct {
+		ID      string         `json:"id"`
+		Lists   []board.List   `json:"lists"`
+		Cards   []cardResponse `json:"cards"`
+		Actions []struct {
+			ID string `json:"id"`
+		} `json:"actions"`
+	}
+	if err := c.getJSON(ctx, u.String(), &raw); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(raw.Lists))
+	for _, l := range raw.Lists {
+		names[l.ID] = l.Name
+	}
+	c.mu.Lock()
+	c.listNames[boardID] = names
+	c.boardIDs[boardID] = raw.ID
+	c.mu.Unlock()
+
+	changes := &board.Changes{Lists: raw.Lists, Cards: make([]board.Card, 0, len(raw.Cards))}
+	for _, rc := range raw.Cards {
+		changes.Cards = append(changes.Cards, rc.toCard(names[rc.IDList]))
+	}
+	if len(raw.Actions) > 0 {
+		changes.Cursor = raw.Actions[0].ID
+	}
+	return changes, nil
+}
+
+// Changes reads the board's action log after since (an action id) and
+// re-fetches only the cards those actions touched.
+func (c *Client) Changes(ctx context.Context, boardID, since string) (*board.Changes, error) {
+	if !c.CanAuth() {
+		return nil, errors.New("missing TRELLO_API_KEY or TRELLO_API_TOKEN")
+	}
+
+	limit := actionPage
+	if since == "" {
+		limit = 1
+	}
+	u, err := url.Parse(baseURL + "/boards/" + boardID + "/actions")
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("key", c.apiKey)
+	q.Set("token", c.token)
+	q.Set("fields", "id,type,data")
+	q.Set("limit", strconv.Itoa(limit))
+	if since != "" {
+		q.Set("since", since)
+	}
+	u.RawQuery = q.Encode()
+
+	var actions []actionResponse
+	if err := c.getJSON(ctx, u.String(), &actions); err != nil {
+		return nil, err
+	}
+
+	changes := &board.Changes{Cursor: since}
+	if len(actions) > 0 {
+		// newest first
+		changes.Cursor = actions[0].ID
+	}
+	if since == "" {
+		return changes, nil
+	}
+	if len(actions) >= actionPage {
+		changes.Reload = true
+		return changes, nil
+	}
+
+	listsChanged := false
+	touched := map[string]bool{}
+	var order []string
+	for i := len(actions) - 1; i >= 0; i-- {
+		a := actions[i]
+		if strings.Contains(a.Type, "List") && a.Data.Card == nil {
+			listsChanged = true
+			continue
+		}
+		if a.Data.Card == nil || a.Data.Card.ID == "" {
+			continue
+		}
+		id := a.Data.Card.ID
+		switch a.Type {
+		case "deleteCard", "moveCardFromBoard":
+			touched[id] = false
+		default:
+			if _, seen := touched[id]; !seen {
+				order = append(order, id)
+			}
+			touched[id] = true
+		}
+	}
+
+	if len(order) > maxCardFetches {
+		changes.Reload = true
+		return changes, nil
+	}
+	if listsChanged {
+		if changes.Lists, err = c.Lists(ctx, boardID); err != nil {
+			return nil, err
+		}
+	}
+
+	fullID, err := c.fullBoardID(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	for id, live := range touched {
+		if !live {
+			changes.Removed = append(changes.Removed, id)
+		}
+	}
+	for _, id := range order {
+		if !touched[id] {
+			continue
+		}
+		card, err := c.card(ctx, id)
+		if errors.Is(err, errNotFound) {
+			changes.Removed = append(changes.Removed, id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if card.Closed || card.IDBoard != fullID {
+			changes.Removed = append(changes.Removed, id)
+			continue
+		}
+		changes.Cards = append(changes.Cards, card.toCard(c.listName(boardID, card.IDList)))
+	}
+	return changes, nil
+}
+
+func (c *Client) card(ctx context.Context, cardID string) (*cardResponse, error) {
+	u, err := url.Parse(baseURL + "/cards/" + cardID)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("key", c.apiKey)
+	q.Set("token", c.token)
+	q.Set("fields", cardFields+",closed,idBoard")
+	u.RawQuery = q.Encode()
+
+	var raw cardResponse
+	if err := c.getJSON(ctx, u.String(), &raw); err != nil {
+		return nil, err
+	}
+	return &raw, nil
+}
@@ -0,0 +1,67 @@
+package trello
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// redirect sends the client's requests to srv instead of api.trello.com.
+type redirect struct {
+	target *url.URL
+}
+
+func (r redirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = r.target.Scheme, r.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func fakeTrello(t *testing.T, h http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	target, _ := url.Parse(srv.URL)
+	c := NewClient("key", "tok")
+	c.http = &http.Client{Transport: redirect{target}}
+	return c
+}
+
+func TestLoadReadsBoardInOneRequest(t *testing.T) {
+	var paths []string
+	c := fakeTrello(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		q := r.URL.Query()
+		if q.Get("cards") != "open" || q.Get("lists") != "open" || q.Get("actions_limit") != "1" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{
+			"id": "5f1a2b3c4d5e6f7a8b9c0d1e",
+			"lists": [{"id": "l1", "name": "Todo"}, {"id": "l2", "name": "Done"}],
+			"cards": [{"id": "c1", "name": "Login", "idList": "l2"}],
+			"actions": [{"id": "a9"}]
+		}`))
+	})
+
+	got, err := c.Load(context.Background(), "abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != "/1/boards/abc123" {
+		t.Fatalf("paths = %v", paths)
+	}
+	if got.Cursor != "a9" || len(got.Lists) != 2 || len(got.Cards) != 1 {
+		t.Fatalf("changes = %+v", got)
+	}
+	if card := got.Cards[0]; card.ListName != "Done" {
+		t.Errorf("card = %+v", card)
+	}
+
+	// the load also resolved the short link, so syncing needs no lookup
+	id, err := c.fullBoardID(context.Background(), "abc123")
+	if err != nil || id != "5f1a2b3c4d5e6f7a8b9c0d1e" || len(paths) != 1 {
+		t.Fatalf("full id = %q, %v after %v", id, err, paths)
+	}
+}
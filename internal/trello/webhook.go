@@ -0,0 +1,300 @@
+package trello
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+var _ board.Watcher = (*Client)(nil)
+
+const (
+	defaultWebhookAddr = "127.0.0.1:8789"
+	// maxWebhookBody caps what a callback may post; real payloads are a few kB.
+	maxWebhookBody = 1 << 20
+)
+
+type webhookResponse struct {
+	ID          string `json:"id"`
+	IDModel     string `json:"idModel"`
+	CallbackURL string `json:"callbackURL"`
+}
+
+type webhookPayload struct {
+	Action actionResponse `json:"action"`
+	Model  struct {
+		ID string `json:"id"`
+	} `json:"model"`
+}
+
+// EnableWebhook makes Watch available. trello posts board actions to
+// callbackURL, a public url that has to reach addr (e.g. through a tunnel);
+// each post is checked against the app secret before it's trusted.
+func (c *Client) EnableWebhook(addr, callbackURL, secret string) {
+	if addr == "" {
+		addr = defaultWebhookAddr
+	}
+	c.hookAddr, c.hookURL, c.hookSecret = addr, callbackURL, secret
+}
+
+// Watch registers a webhook for the board and forwards its actions until
+// ctx is cancelled, when the webhook is removed again. the listener is
+// started by the first watch and shared by the ones after it, so switching
+// boards doesn't race the old watch for the address.
+func (c *Client) Watch(ctx context.Context, boardID string) (<-chan board.Event, error) {
+	if c.hookURL == "" {
+		return nil, fmt.Errorf("trello webhooks need TRELLO_WEBHOOK_URL: %w", board.ErrUnsupported)
+	}
+	if c.hookSecret == "" {
+		return nil, errors.New("TRELLO_API_SECRET is required to verify trello webhooks")
+	}
+
+	// payloads and webhooks carry the board's full id, not its short link
+	fullID, err := c.fullBoardID(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	h, err := c.webhookServer()
+	if err != nil {
+		return nil, err
+	}
+	// trello calls the url back while the webhook is created, so the
+	// listener has to be up first
+	sink := h.add(fullID, boardID)
+	id, err := c.registerWebhook(ctx, fullID)
+	if err != nil {
+		h.remove(fullID, sink)
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	c.watchers[id] = cancel
+	c.mu.Unlock()
+	c.watches.Add(1)
+	go func() {
+		defer c.watches.Done()
+		<-ctx.Done()
+		h.remove(fullID, sink)
+		cleanup, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+		_ = c.deleteReq(cleanup, "/webhooks/"+id)
+		c.mu.Lock()
+		delete(c.watchers, id)
+		c.mu.Unlock()
+	}()
+	return sink.events, nil
+}
+
+// webhookServer starts the webhook listener once; a failed start is tried
+// again by the next watch.
+func (c *Client) webhookServer() (*webhookHandler, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.hooks != nil {
+		return c.hooks, nil
+	}
+	ln, err := net.Listen("tcp", c.hookAddr)
+	if err != nil {
+		return nil, fmt.Errorf("webhook listener: %w", err)
+	}
+	h := newWebhookHandler(c.hookSecret, c.hookURL)
+	srv := &http.Server{Handler: h, ReadHeaderTimeout: 10 * time.Second}
+	go srv.Serve(ln)
+	c.hooks, c.hookServer = h, srv
+	return h, nil
+}
+
+// Close stops running watches, removes their webhooks and shuts the
+// listener down.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	for _, stop := range c.watchers {
+		stop()
+	}
+	c.mu.Unlock()
+	c.watches.Wait()
+
+	c.mu.Lock()
+	srv := c.hookServer
+	c.hooks, c.hookServer = nil, nil
+	c.mu.Unlock()
+	if srv == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
+func (c *Client) registerWebhook(ctx context.Context, boardID string) (string, error) {
+	var hook webhookResponse
+	vals := url.Values{"callbackURL": {c.hookURL}, "idModel": {boardID}, "description": {"aboard"}}
+	err := c.postForm(ctx, "/webhooks", vals, &hook)
+	if err == nil {
+		return hook.ID, nil
+	}
+	// trello refuses duplicates, so one left behind by a session that didn't
+	// shut down cleanly is taken over
+	if id, ferr := c.findWebhook(ctx, boardID); ferr == nil && id != "" {
+		return id, nil
+	}
+	return "", fmt.Errorf("register webhook: %w", err)
+}
+
+func (c *Client) findWebhook(ctx context.Context, boardID string) (string, error) {
+	u, err := url.Parse(baseURL + "/tokens/" + c.token + "/webhooks")
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("key", c.apiKey)
+	q.Set("token", c.token)
+	u.RawQuery = q.Encode()
+
+	var hooks []webhookResponse
+	if err := c.getJSON(ctx, u.String(), &hooks); err != nil {
+		return "", err
+	}
+	for _, h := range hooks {
+		if h.IDModel == boardID && h.CallbackURL == c.hookURL {
+			return h.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// webhookHandler receives trello's callbacks and hands each to the watch of
+// the board it's about.
+type webhookHandler struct {
+	secret      string
+	callbackURL string
+
+	mu    sync.Mutex
+	sinks map[string]*webhookSink // by full board id
+}
+
+// webhookSink is one watch's event stream.
+type webhookSink struct {
+	boardID string // as the watch was asked for, which events report
+
+	mu     sync.Mutex
+	closed bool
+	events chan board.Event
+}
+
+func newWebhookHandler(secret, callbackURL string) *webhookHandler {
+	return &webhookHandler{
+		secret:      secret,
+		callbackURL: callbackURL,
+		sinks:       make(map[string]*webhookSink),
+	}
+}
+
+// add routes the board's callbacks to a new sink, replacing an older watch
+// of the same board that is still winding down.
+func (h *webhookHandler) add(fullID, boardID string) *webhookSink {
+	s := &webhookSink{boardID: boardID, events: make(chan board.Event, 16)}
+	h.mu.Lock()
+	h.sinks[fullID] = s
+	h.mu.Unlock()
+	return s
+}
+
+// remove closes the sink and stops routing to it, unless a newer watch of
+// the board has taken its place.
+func (h *webhookHandler) remove(fullID string, s *webhookSink) {
+	h.mu.Lock()
+	if h.sinks[fullID] == s {
+		delete(h.sinks, fullID)
+	}
+	h.mu.Unlock()
+	s.close()
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodHead, http.MethodGet:
+		// trello's check that the callback url answers
+		w.WriteHeader(http.StatusOK)
+		return
+	case http.MethodPost:
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if !verifyWebhook(h.secret, h.callbackURL, body, r.Header.Get("X-Trello-Webhook")) {
+		http.Error(w, "bad signature", http.StatusUnauthorized)
+		return
+	}
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "bad payload", http.StatusBadRequest)
+		return
+	}
+	h.mu.Lock()
+	s := h.sinks[payload.Model.ID]
+	h.mu.Unlock()
+	if s != nil {
+		ev := board.Event{BoardID: s.boardID, Type: payload.Action.Type}
+		if payload.Action.Data.Card != nil {
+			ev.CardID = payload.Action.Data.Card.ID
+		}
+		s.send(ev)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// send never blocks: a full buffer already means a sync is due, and one sync
+// picks up every change behind it.
+func (s *webhookSink) send(ev board.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.events <- ev:
+	default:
+	}
+}
+
+func (s *webhookSink) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+}
+
+// verifyWebhook checks trello's signature: base64 hmac-sha1 of the body
+// followed by the callback url, keyed with the app secret.
+func verifyWebhook(secret, callbackURL string, body []byte, signature string) bool {
+	got, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	mac.Write([]byte(callbackURL))
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,143 @@
+package trello
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testSecret   = "app-secret"
+	testCallback = "https://example.ngrok.app/trello"
+)
+
+// a trimmed updateCard callback as trello posts it
+const updateCardPayload = `{
+	"model": {"id": "5f1a2b3c4d5e6f7a8b9c0d1e", "name": "Roadmap"},
+	"action": {
+		"id": "65a0c0ffee0000000000beef",
+		"type": "updateCard",
+		"data": {
+			"card": {"id": "65a0c0ffee0000000000c4rd", "name": "Fix login", "idList": "65a0c0ffee00000000001157"},
+			"old": {"name": "Login bug"},
+			"board": {"id": "5f1a2b3c4d5e6f7a8b9c0d1e", "shortLink": "abc123"}
+		}
+	}
+}`
+
+func sign(body string) string {
+	mac := hmac.New(sha1.New, []byte(testSecret))
+	mac.Write([]byte(body))
+	mac.Write([]byte(testCallback))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func post(h http.Handler, body, signature string) int {
+	req := httptest.NewRequest(http.MethodPost, "/trello", strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set("X-Trello-Webhook", signature)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(updateCardPayload)
+	if !verifyWebhook(testSecret, testCallback, body, sign(updateCardPayload)) {
+		t.Error("valid signature rejected")
+	}
+	if verifyWebhook(testSecret, testCallback+"/other", body, sign(updateCardPayload)) {
+		t.Error("signature for another callback url accepted")
+	}
+	if verifyWebhook("wrong", testCallback, body, sign(updateCardPayload)) {
+		t.Error("signature with another secret accepted")
+	}
+	if verifyWebhook(testSecret, testCallback, body, "") {
+		t.Error("missing signature accepted")
+	}
+}
+
+func TestWebhookRejectsBadSignature(t *testing.T) {
+	h := newWebhookHandler(testSecret, testCallback)
+	sink := h.add("5f1a2b3c4d5e6f7a8b9c0d1e", "abc123")
+
+	for _, sig := range []string{"", "not base64!", base64.StdEncoding.EncodeToString([]byte("forged"))} {
+		if code := post(h, updateCardPayload, sig); code != http.StatusUnauthorized {
+			t.Errorf("signature %q: status %d, want 401", sig, code)
+		}
+	}
+	select {
+	case ev := <-sink.events:
+		t.Fatalf("unsigned payload delivered: %+v", ev)
+	default:
+	}
+}
+
+func TestWebhookAnswersHead(t *testing.T) {
+	h := newWebhookHandler(testSecret, testCallback)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/trello", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+}
+
+func TestWebhookRoutesByFullBoardID(t *testing.T) {
+	h := newWebhookHandler(testSecret, testCallback)
+	sink := h.add("5f1a2b3c4d5e6f7a8b9c0d1e", "abc123")
+	other := h.add("000000000000000000000000", "zzz999")
+
+	if code := post(h, updateCardPayload, sign(updateCardPayload)); code != http.StatusOK {
+		t.Fatalf("status %d, want 200", code)
+	}
+	select {
+	case ev := <-sink.events:
+		if ev.BoardID != "abc123" || ev.Type != "updateCard" || ev.CardID != "65a0c0ffee0000000000c4rd" {
+			t.Fatalf("event = %+v", ev)
+		}
+	default:
+		t.Fatal("no event delivered")
+	}
+	select {
+	case ev := <-other.events:
+		t.Fatalf("event for another board delivered: %+v", ev)
+	default:
+	}
+
+	// a board nobody watches is acknowledged and dropped
+	h.remove("5f1a2b3c4d5e6f7a8b9c0d1e", sink)
+	if code := post(h, updateCardPayload, sign(updateCardPayload)); code != http.StatusOK {
+		t.Fatalf("status %d, want 200", code)
+	}
+	if _, open := <-sink.events; open {
+		t.Fatal("removed sink still open")
+	}
+}
+
+func TestWebhookReplacedWatchKeepsNewSink(t *testing.T) {
+	h := newWebhookHandler(testSecret, testCallback)
+	old := h.add("5f1a2b3c4d5e6f7a8b9c0d1e", "abc123")
+	next := h.add("5f1a2b3c4d5e6f7a8b9c0d1e", "abc123")
+
+	// the old watch winds down after the new one started
+	h.remove("5f1a2b3c4d5e6f7a8b9c0d1e", old)
+	if _, open := <-old.events; open {
+		t.Fatal("old sink still open")
+	}
+	if code := post(h, updateCardPayload, sign(updateCardPayload)); code != http.StatusOK {
+		t.Fatalf("status %d, want 200", code)
+	}
+	select {
+	case ev, open := <-next.events:
+		if !open || ev.CardID == "" {
+			t.Fatalf("newer sink closed or empty: %+v", ev)
+		}
+	default:
+		t.Fatal("newer sink got nothing")
+	}
+}
@@ -2,10 +2,13 @@ package ui
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/codywilliamson/aboard/internal/board"
 )
 
 type agentAction struct {
@@ -15,6 +18,42 @@ type agentAction struct {
 	Name   string `json:"name,omitempty"`
 	Desc   string `json:"desc,omitempty"`
 	Text   string `json:"text,omitempty"`
+	Label  string `json:"label,omitempty"`  // label id or name
+	Member string `json:"member,omitempty"` // member id, username, full name or "me"
+	Due    string `json:"due,omitempty"`    // as typed in the due prompt; "none" clears
+
+	// checklist actions
+	Checklist string `json:"checklist,omitempty"` // checklist id or name
+	Item      string `json:"item,omitempty"`      // check item id or name
+	Checked   *bool  `json:"checked,omitempty"`   // check_item: defaults to true
+}
+
+// checkedState is what check_item sets an item to.
+func (a agentAction) checkedState() bool {
+	return a.Checked == nil || *a.Checked
+}
+
+// cardUpdate maps the optional name/desc of an update_card action onto a
+// partial update. empty strings mean "leave unchanged".
+func (a agentAction) cardUpdate() board.CardUpdate {
+	var u board.CardUpdate
+	if a.Name != "" {
+		u.Name = &a.Name
+	}
+	if a.Desc != "" {
+		u.Desc = &a.Desc
+	}
+	return u
+}
+
+// destructive actions remove things from the board. undo counts: undoing a
+// create or a checklist item conversion archives what was made.
+func (a agentAction) destructive() bool {
+	switch a.Type {
+	case "archive_card", "archive_list", "undo":
+		return true
+	}
+	return false
 }
 
 var actionRe = regexp.MustCompile(`<action>(.*?)</action>`)
@@ -44,39 +83,85 @@ func parseActions(raw string) (string, []agentAction) {
 	return display, actions
 }
 
-// executeActions converts parsed actions into mutation commands.
-func executeActions(client trelloClient, boardID string, actions []agentAction) tea.Cmd {
+// executeActions converts parsed actions into mutation commands, applying
+// them to k right away.
+func executeActions(client board.BoardProvider, boardID string, k *KanbanModel, actions []agentAction) tea.Cmd {
 	var cmds []tea.Cmd
 	for _, a := range actions {
 		switch a.Type {
 		case "move_card":
 			if a.CardID != "" && a.ListID != "" {
-				cmds = append(cmds, moveCardCmd(client, a.CardID, a.ListID))
+				cmds = append(cmds, moveCard(client, k, a.CardID, a.ListID))
 			}
 		case "update_card":
 			if a.CardID != "" && (a.Name != "" || a.Desc != "") {
-				cmds = append(cmds, updateCardCmd(client, a.CardID, a.Name, a.Desc))
+				cmds = append(cmds, updateCard(client, k, a.CardID, a.cardUpdate()))
 			}
 		case "add_comment":
 			if a.CardID != "" && a.Text != "" {
-				cmds = append(cmds, addCommentCmd(client, a.CardID, a.Text))
+				cmds = append(cmds, addCommentCmd(client, a.CardID, a.Text, k.findCard(a.CardID)))
 			}
 		case "archive_card":
 			if a.CardID != "" {
-				cmds = append(cmds, archiveCardCmd(client, a.CardID))
+				cmds = append(cmds, archiveCard(client, k, a.CardID))
 			}
 		case "create_card":
 			if a.ListID != "" && a.Name != "" {
-				cmds = append(cmds, createCardCmd(client, a.ListID, a.Name))
+				cmds = append(cmds, createCard(client, k, a.ListID, a.Name))
 			}
 		case "create_list":
 			if a.Name != "" {
-				cmds = append(cmds, createListCmd(client, boardID, a.Name))
+				cmds = append(cmds, createList(client, k, boardID, a.Name))
 			}
 		case "archive_list":
 			if a.ListID != "" {
-				cmds = append(cmds, archiveListCmd(client, a.ListID))
+				cmds = append(cmds, archiveList(client, k, a.ListID))
+			}
+		case "add_label", "remove_label":
+			if a.CardID == "" || a.Label == "" {
+				continue
+			}
+			if l := k.findLabel(a.Label); l != nil {
+				cmds = append(cmds, labelCard(client, k, a.CardID, *l, a.Type == "add_label"))
+			} else {
+				err := fmt.Errorf("no label %q on this board", a.Label)
+				cmds = append(cmds, func() tea.Msg { return cardMutatedMsg{action: "label", cardID: a.CardID, err: err} })
+			}
+		case "assign_member", "unassign_member":
+			if a.CardID == "" || a.Member == "" {
+				continue
+			}
+			if mem := k.findMember(a.Member); mem != nil {
+				cmds = append(cmds, assignMember(client, k, a.CardID, *mem, a.Type == "assign_member"))
+			} else {
+				err := fmt.Errorf("no member %q on this board", a.Member)
+				cmds = append(cmds, func() tea.Msg { return cardMutatedMsg{action: "assign", cardID: a.CardID, err: err} })
+			}
+		case "set_due":
+			if a.CardID == "" || a.Due == "" {
+				continue
+			}
+			if due, err := parseDue(a.Due, time.Now()); err == nil {
+				cmds = append(cmds, setCardDue(client, k, a.CardID, due))
+			} else {
+				cmds = append(cmds, func() tea.Msg { return cardMutatedMsg{action: "due", cardID: a.CardID, err: err} })
+			}
+		case "add_checklist_item":
+			if a.CardID != "" && a.Text != "" {
+				cmds = append(cmds, addCheckItemCmd(client, a.CardID, a.Checklist, a.Text))
+			}
+		case "check_item":
+			if a.CardID == "" || a.Item == "" {
+				continue
+			}
+			if _, it := k.findCheckItem(a.CardID, a.Item); it != nil {
+				cmds = append(cmds, checkItem(client, k, a.CardID, *it, a.checkedState()))
+			} else {
+				err := fmt.Errorf("no checklist item %q on that card", a.Item)
+				cmds = append(cmds, func() tea.Msg { return cardMutatedMsg{action: "check item", cardID: a.CardID, err: err} })
 			}
+		case "undo":
+			cmds = append(cmds, func() tea.Msg { return undoRequestMsg{} })
 		}
 	}
 	if len(cmds) == 0 {
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/codywilliamson/aboard/internal/board"
 )
 
 func (m Model) updateBoardSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -22,7 +23,7 @@ func (m Model) updateBoardSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			selected := m.boards[m.boardCursor]
 			m.loading = true
 			m.status = fmt.Sprintf("loading %q...", selected.Name)
-			return m, loadBoardDataCmd(m.trello, selected.ID, selected.Name)
+			return m, m.loadBoardCmd(selected.ID, selected.Name)
 		}
 	case "q":
 		return m, tea.Quit
@@ -69,7 +70,11 @@ func (m *Model) renderBoardSelect() string {
 		b.WriteString("\n")
 	}
 	b.WriteString("\n")
-	b.WriteString(subtleStyle.Render("enter: open   j/k: navigate   r: refresh   ?: help   q: quit"))
+	keys := "enter: open   j/k: navigate   r: refresh   ?: help   q: quit"
+	if _, ok := m.provider.(board.Searcher); ok {
+		keys = "enter: open   j/k: navigate   ctrl+f: search   r: refresh   ?: help   q: quit"
+	}
+	b.WriteString(subtleStyle.Render(keys))
 
 	return baseStyle.Render(b.String())
 }
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// maxCheckLines caps the checklist rows in the drawer so the timeline keeps
+// its room; the window follows the item cursor.
+const maxCheckLines = 10
+
+// defaultChecklist names the checklist created for a card's first item.
+const defaultChecklist = "Checklist"
+
+// checkProgress renders a card's checklist progress for its row, with a
+// leading space, e.g. " 3/7".
+func checkProgress(c board.Card) string {
+	if c.CheckItems == 0 {
+		return ""
+	}
+	text := fmt.Sprintf("%d/%d", c.CheckItemsChecked, c.CheckItems)
+	if c.CheckItemsChecked == c.CheckItems {
+		return " " + dueDoneStyle.Render(text)
+	}
+	return " " + subtleStyle.Render(text)
+}
+
+func checklistTotals(lists []board.Checklist) (total, checked int) {
+	for _, l := range lists {
+		for _, it := range l.Items {
+			total++
+			if it.Checked {
+				checked++
+			}
+		}
+	}
+	return total, checked
+}
+
+// checkItemAt returns the i-th item across lists, in display order.
+func checkItemAt(lists []board.Checklist, i int) (checklistID string, item *board.CheckItem) {
+	for li := range lists {
+		if i < len(lists[li].Items) {
+			return lists[li].ID, &lists[li].Items[i]
+		}
+		i -= len(lists[li].Items)
+	}
+	return "", nil
+}
+
+// checklistLines renders lists for the drawer, marking the cursor's item.
+// at most maxCheckLines are returned, scrolled to keep the cursor in view.
+func checklistLines(lists []board.Checklist, cursor, width int) []string {
+	var lines []string
+	at := -1
+	n := 0
+	for _, l := range lists {
+		total, checked := checklistTotals([]board.Checklist{l})
+		lines = append(lines, fmt.Sprintf("%s %s", ellipsis(l.Name, max(10, width-10)), subtleStyle.Render(fmt.Sprintf("%d/%d", checked, total))))
+		for _, it := range l.Items {
+			box := "[ ] "
+			if it.Checked {
+				box = "[x] "
+			}
+			line := box + ellipsis(it.Name, max(10, width-8))
+			switch {
+			case n == cursor:
+				at = len(lines)
+				line = selectedRowStyle.Render("> " + line)
+			case it.Checked:
+				line = subtleStyle.Render("  " + line)
+			default:
+				line = "  " + line
+			}
+			lines = append(lines, line)
+			n++
+		}
+	}
+	if len(lines) <= maxCheckLines {
+		return lines
+	}
+	from := max(0, min(at-maxCheckLines/2, len(lines)-maxCheckLines))
+	return lines[from : from+maxCheckLines]
+}
+
+// checklistsStale reports whether the cached checklists of a card no longer
+// match its progress counts, or were never loaded for a card that has items.
+func (k *KanbanModel) checklistsStale(c *board.Card) bool {
+	lists, ok := k.checklists[c.ID]
+	if !ok {
+		return c.CheckItems > 0
+	}
+	total, checked := checklistTotals(lists)
+	return total != c.CheckItems || checked != c.CheckItemsChecked
+}
+
+// findCheckItem resolves an item on a card's loaded checklists by id, or by
+// name as an agent might refer to it.
+func (k *KanbanModel) findCheckItem(cardID, ref string) (checklistID string, item *board.CheckItem) {
+	lists := k.checklists[cardID]
+	for _, match := range []func(board.CheckItem) bool{
+		func(it board.CheckItem) bool { return it.ID == ref },
+		func(it board.CheckItem) bool { return strings.EqualFold(it.Name, ref) },
+	} {
+		for li := range lists {
+			for ii := range lists[li].Items {
+				if match(lists[li].Items[ii]) {
+					return lists[li].ID, &lists[li].Items[ii]
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// checkItemRef describes the item an action names, for the review queue.
+func (k *KanbanModel) checkItemRef(cardID, ref string) string {
+	if _, it := k.findCheckItem(cardID, ref); it != nil {
+		return fmt.Sprintf("%q", it.Name)
+	}
+	if ref == "" {
+		return "(no item)"
+	}
+	return fmt.Sprintf("%q (not loaded)", ref)
+}
+
+// findChecklist resolves a checklist by id or name; an empty ref is the
+// card's first checklist.
+func findChecklist(lists []board.Checklist, ref string) *board.Checklist {
+	for i := range lists {
+		if ref == "" || lists[i].ID == ref || strings.EqualFold(lists[i].Name, ref) {
+			return &lists[i]
+		}
+	}
+	return nil
+}
@@ -5,54 +5,227 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/codywilliamson/aboard/internal/trello"
+	"github.com/codywilliamson/aboard/internal/board"
 )
 
 type timelineEntry struct {
-	stamp string
-	who   string
-	text  string
+	at        time.Time
+	stamp     string
+	who       string
+	text      string
+	streaming bool
 }
 
 type DrawerModel struct {
-	card     *trello.Card
+	card     *board.Card
 	timeline viewport.Model
 	entries  []timelineEntry
+	spinner  spinner.Model
+	review   reviewQueue
 	width    int
 	height   int
+
+	// the card's checklists once loaded; checkCursor counts items across them
+	checklists  []board.Checklist
+	checkCursor int
+	// the card's comments and history on the provider, oldest first
+	activity []board.Activity
+	// the card's members by name, set along with the card
+	members string
 }
 
 func NewDrawerModel() DrawerModel {
+	sp := spinner.New()
+	sp.Spinner = spinner.Dot
+	sp.Style = infoStyle
 	return DrawerModel{
 		timeline: viewport.New(60, 10),
+		spinner:  sp,
 	}
 }
 
-func (d *DrawerModel) SetCard(card *trello.Card) {
-	hadCard := d.card != nil
+func (d *DrawerModel) SetCard(card *board.Card) {
+	if card == nil || d.card == nil || card.ID != d.card.ID {
+		d.checklists = nil
+		d.checkCursor = 0
+		if d.activity != nil {
+			d.activity = nil
+			d.rebuildTimeline()
+		}
+	}
 	d.card = card
-	hasCard := d.card != nil
-	if hadCard != hasCard && d.width > 0 {
+	// the detail panel's height depends on the card
+	if d.width > 0 {
+		d.Resize(d.width, d.height)
+	}
+}
+
+func (d *DrawerModel) SetChecklists(lists []board.Checklist) {
+	d.checklists = lists
+	total, _ := checklistTotals(lists)
+	d.checkCursor = max(0, min(d.checkCursor, total-1))
+	if d.width > 0 {
+		d.Resize(d.width, d.height)
+	}
+}
+
+func (d *DrawerModel) MoveCheck(delta int) {
+	total, _ := checklistTotals(d.checklists)
+	d.checkCursor = max(0, min(d.checkCursor+delta, total-1))
+}
+
+// CurrentCheckItem is the item under the checklist cursor.
+func (d *DrawerModel) CurrentCheckItem() (checklistID string, item *board.CheckItem) {
+	return checkItemAt(d.checklists, d.checkCursor)
+}
+
+// SetMembers names the card's members in the detail panel.
+func (d *DrawerModel) SetMembers(names string) {
+	d.members = names
+	if d.width > 0 {
 		d.Resize(d.width, d.height)
 	}
 }
 
+// SetActivity shows the card's provider history in the timeline, merged with
+// the local entries by time.
+func (d *DrawerModel) SetActivity(activity []board.Activity) {
+	d.activity = activity
+	d.rebuildTimeline()
+}
+
 func (d *DrawerModel) AppendTimeline(who, text string) {
-	stamp := time.Now().Format("15:04:05")
-	d.entries = append(d.entries, timelineEntry{stamp: stamp, who: who, text: strings.TrimSpace(text)})
+	now := time.Now()
+	d.entries = append(d.entries, timelineEntry{at: now, stamp: now.Format("15:04:05"), who: who, text: strings.TrimSpace(text)})
+	d.rebuildTimeline()
+	d.timeline.GotoBottom()
+}
+
+// BeginStream opens a timeline entry that AppendStream grows as agent
+// output arrives.
+func (d *DrawerModel) BeginStream(who string) {
+	now := time.Now()
+	d.entries = append(d.entries, timelineEntry{at: now, stamp: now.Format("15:04:05"), who: who, streaming: true})
+	d.rebuildTimeline()
+	d.timeline.GotoBottom()
+}
+
+func (d *DrawerModel) AppendStream(text string) {
+	e := d.streamingEntry()
+	if e == nil {
+		return
+	}
+	e.text += text
+	d.rebuildTimeline()
+}
+
+// EndStream replaces the raw streamed text with its final form. an empty
+// final text drops the entry.
+func (d *DrawerModel) EndStream(text string) {
+	for i := len(d.entries) - 1; i >= 0; i-- {
+		if !d.entries[i].streaming {
+			continue
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			d.entries = append(d.entries[:i], d.entries[i+1:]...)
+		} else {
+			d.entries[i].text = text
+			d.entries[i].streaming = false
+		}
+		break
+	}
 	d.rebuildTimeline()
 }
 
+func (d *DrawerModel) Streaming() bool {
+	return d.streamingEntry() != nil
+}
+
+func (d *DrawerModel) streamingEntry() *timelineEntry {
+	for i := len(d.entries) - 1; i >= 0; i-- {
+		if d.entries[i].streaming {
+			return &d.entries[i]
+		}
+	}
+	return nil
+}
+
+// QueueReview adds actions to the approval checklist shown above the timeline.
+func (d *DrawerModel) QueueReview(actions []agentAction, k *KanbanModel) {
+	d.review.Add(actions, k)
+	d.Resize(d.width, d.height)
+}
+
+// TakeReview closes the checklist, returning the approved actions and the
+// number dropped.
+func (d *DrawerModel) TakeReview() ([]agentAction, int) {
+	approved, dropped := d.review.Take()
+	d.Resize(d.width, d.height)
+	return approved, dropped
+}
+
+// TickSpinner advances the running-agent spinner.
+func (d *DrawerModel) TickSpinner(msg spinner.TickMsg) tea.Cmd {
+	var cmd tea.Cmd
+	d.spinner, cmd = d.spinner.Update(msg)
+	if d.Streaming() {
+		d.rebuildTimeline()
+	}
+	return cmd
+}
+
+// rebuildTimeline re-renders entries, staying pinned to the bottom only if
+// the user hasn't scrolled up.
 func (d *DrawerModel) rebuildTimeline() {
+	follow := d.timeline.AtBottom()
 	var parts []string
+	next := 0
 	for _, e := range d.entries {
-		parts = append(parts, fmt.Sprintf("[%s] %s\n%s", e.stamp, e.who, e.text))
+		for ; next < len(d.activity) && d.activity[next].At.Before(e.at); next++ {
+			parts = append(parts, renderActivity(d.activity[next]))
+		}
+		head := fmt.Sprintf("[%s] %s", e.stamp, e.who)
+		text := e.text
+		if e.streaming {
+			head += " " + d.spinner.View()
+			text = strings.TrimSpace(text)
+			if text == "" {
+				text = subtleStyle.Render("waiting for output...")
+			}
+		}
+		parts = append(parts, head+"\n"+text)
+	}
+	for _, a := range d.activity[next:] {
+		parts = append(parts, renderActivity(a))
 	}
 	content := strings.Join(parts, "\n\n")
 	d.timeline.SetContent(content)
-	d.timeline.GotoBottom()
+	if follow {
+		d.timeline.GotoBottom()
+	}
+}
+
+// renderActivity styles a provider comment or change apart from the local
+// timeline: comments keep their text, changes fit on one line.
+func renderActivity(a board.Activity) string {
+	t := a.At.Local()
+	stamp := t.Format("15:04:05")
+	if t.Format("2006-01-02") != time.Now().Format("2006-01-02") {
+		stamp = t.Format("Jan 2 15:04")
+	}
+	author := a.Author
+	if author == "" {
+		author = "someone"
+	}
+	if a.Kind == "comment" {
+		return activityStyle.Render(fmt.Sprintf("[%s] %s commented", stamp, author)) + "\n" + a.Text
+	}
+	return activityStyle.Render(fmt.Sprintf("[%s] %s", stamp, author)) + " " + subtleStyle.Render(a.Text)
 }
 
 func (d *DrawerModel) Resize(w, h int) {
@@ -63,7 +236,21 @@ func (d *DrawerModel) Resize(w, h int) {
 	overhead := 4
 	if d.card != nil {
 		overhead = 12
+		if len(d.card.Labels) > 0 {
+			overhead++
+		}
+		if d.members != "" {
+			overhead++
+		}
+		if d.card.Due != nil {
+			overhead++
+		}
+		if d.card.Start != nil {
+			overhead++
+		}
+		overhead += len(checklistLines(d.checklists, d.checkCursor, d.width))
 	}
+	overhead += d.review.Height()
 	d.timeline.Height = max(3, h-overhead)
 }
 
@@ -74,10 +261,13 @@ func (d *DrawerModel) View() string {
 	if d.card != nil {
 		sections = append(sections, d.renderCardDetail(innerWidth), "")
 	}
+	if d.review.Active() {
+		sections = append(sections, d.review.View(innerWidth))
+	}
 
 	timelineTitle := lipgloss.NewStyle().Bold(true).Render("─── Timeline ───")
 	var timelineContent string
-	if len(d.entries) == 0 {
+	if len(d.entries) == 0 && len(d.activity) == 0 {
 		timelineContent = subtleStyle.Render("No output yet. Send a prompt with / or tab.")
 	} else {
 		timelineContent = d.timeline.View()
@@ -111,12 +301,30 @@ func (d *DrawerModel) renderCardDetail(width int) string {
 
 	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("229")).Render(ellipsis(card.Name, width-2))
 
-	return lipgloss.JoinVertical(lipgloss.Left,
+	lines := []string{
 		lipgloss.NewStyle().Bold(true).Render("Card Detail"),
 		title,
-		"list: "+listBadge(card.ListName),
+		"list: " + listBadge(card.ListName),
+	}
+	if len(card.Labels) > 0 {
+		lines = append(lines, "labels: "+labelList(card.Labels))
+	}
+	if d.members != "" {
+		lines = append(lines, "members: "+memberStyle.Render(d.members))
+	}
+	if card.Start != nil {
+		lines = append(lines, "start: "+formatDue(*card.Start))
+	}
+	if card.Due != nil {
+		now := time.Now()
+		rel := dueStyle(*card.Due, card.DueComplete, now).Render(dueText(*card.Due, card.DueComplete, now))
+		lines = append(lines, "due: "+formatDue(*card.Due)+" "+rel)
+	}
+	lines = append(lines, checklistLines(d.checklists, d.checkCursor, width)...)
+	lines = append(lines,
 		subtleStyle.Render("url: "+url),
 		"",
 		subtleStyle.Render(desc),
 	)
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
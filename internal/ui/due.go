@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+const (
+	// defaultDueHour is used when a due date is given without a time.
+	defaultDueHour = 17
+	// maxContextDue caps the due dates listed in agent context.
+	maxContextDue = 20
+)
+
+var (
+	relativeDueRe = regexp.MustCompile(`^(?:\+|in\s+)(\d+)\s*(m|min|mins|minutes?|h|hrs?|hours?|d|days?|w|wks?|weeks?)$`)
+	clockRe       = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+// parseDue reads a due date the way people type one: "tomorrow 5pm", "+3d",
+// "in 2 hours", "fri", "mar 15 9am", "2024-03-15 14:00", "2024-03-15T14:00:00Z".
+// "none", "clear" or "-" clear it, which is reported as a nil time.
+func parseDue(input string, now time.Time) (*time.Time, error) {
+	trimmed := strings.Join(strings.Fields(input), " ")
+	// timestamps are matched as typed: lowercasing would break the T and Z
+	// of an iso one, which is what agents send
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, trimmed, now.Location()); err == nil {
+			return &t, nil
+		}
+	}
+
+	s := strings.ToLower(trimmed)
+	switch s {
+	case "", "none", "clear", "-":
+		return nil, nil
+	}
+
+	if m := relativeDueRe.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		var t time.Time
+		switch m[2][0] {
+		case 'm':
+			t = now.Add(time.Duration(n) * time.Minute)
+		case 'h':
+			t = now.Add(time.Duration(n) * time.Hour)
+		case 'd':
+			t = now.AddDate(0, 0, n)
+		case 'w':
+			t = now.AddDate(0, 0, 7*n)
+		}
+		return &t, nil
+	}
+
+	// a day, optionally followed by a clock time
+	day, clock := s, ""
+	if i := strings.LastIndex(s, " "); i > 0 && isClock(s[i+1:]) {
+		day, clock = s[:i], s[i+1:]
+	}
+	date, ok := parseDueDay(day, now)
+	if !ok {
+		// a bare time means today, or tomorrow once it has passed
+		if isClock(s) {
+			date, clock = now, s
+		} else {
+			return nil, fmt.Errorf("can't read %q as a due date", input)
+		}
+	}
+	hour, minute := defaultDueHour, 0
+	if clock != "" {
+		var err error
+		if hour, minute, err = parseClock(clock); err != nil {
+			return nil, err
+		}
+	}
+	t := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, now.Location())
+	if !ok && !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// parseDueDay resolves the date part. weekdays mean the next one after today.
+func parseDueDay(s string, now time.Time) (time.Time, bool) {
+	switch s {
+	case "today", "tonight":
+		return now, true
+	case "tomorrow", "tmrw", "tmr":
+		return now.AddDate(0, 0, 1), true
+	}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if s == name || s == name[:3] {
+			ahead := (int(wd) - int(now.Weekday()) + 7) % 7
+			if ahead == 0 {
+				ahead = 7
+			}
+			return now.AddDate(0, 0, ahead), true
+		}
+	}
+	for _, layout := range []string{"2006-01-02", "Jan 2", "January 2", "2 Jan", "1/2"} {
+		t, err := time.ParseInLocation(layout, s, now.Location())
+		if err != nil {
+			continue
+		}
+		if t.Year() == 0 {
+			// no year given: the next time that date comes round
+			t = t.AddDate(now.Year(), 0, 0)
+			if t.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())) {
+				t = t.AddDate(1, 0, 0)
+			}
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// isClock wants minutes or am/pm, so a bare number like "5" isn't taken
+// for a time.
+func isClock(s string) bool {
+	if s == "noon" {
+		return true
+	}
+	m := clockRe.FindStringSubmatch(s)
+	return m != nil && (m[2] != "" || m[3] != "")
+}
+
+func parseClock(s string) (hour, minute int, err error) {
+	if s == "noon" {
+		return 12, 0, nil
+	}
+	m := clockRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, fmt.Errorf("can't read %q as a time", s)
+	}
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	switch {
+	case m[3] == "pm" && hour < 12:
+		hour += 12
+	case m[3] == "am" && hour == 12:
+		hour = 0
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, fmt.Errorf("can't read %q as a time", s)
+	}
+	return hour, minute, nil
+}
+
+// dueText is the short relative form shown on card rows: "in 2d",
+// "overdue 3h", or a check once done.
+func dueText(due time.Time, complete bool, now time.Time) string {
+	if complete {
+		return "✓"
+	}
+	d := due.Sub(now)
+	if d < 0 {
+		return "overdue " + shortDuration(-d)
+	}
+	return "in " + shortDuration(d)
+}
+
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", max(1, int(d.Minutes())))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// dueStyle colors due info by urgency.
+func dueStyle(due time.Time, complete bool, now time.Time) lipgloss.Style {
+	switch {
+	case complete:
+		return dueDoneStyle
+	case due.Before(now):
+		return overdueStyle
+	case due.Sub(now) < 24*time.Hour:
+		return dueSoonStyle
+	default:
+		return subtleStyle
+	}
+}
+
+// formatDue is the absolute form shown in the drawer and the review queue.
+func formatDue(t time.Time) string {
+	return t.Local().Format("Mon Jan 2 15:04")
+}
+
+// dueInput is the form the editor is prefilled with; parseDue reads it back.
+func dueInput(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// dueCards lists cards with an open due date, soonest first.
+func (k *KanbanModel) dueCards() []board.Card {
+	var due []board.Card
+	for _, list := range k.lists {
+		for _, c := range k.cards[list.ID] {
+			if c.Due != nil && !c.DueComplete && k.shown(c) {
+				due = append(due, c)
+			}
+		}
+	}
+	slices.SortStableFunc(due, func(a, b board.Card) int { return a.Due.Compare(*b.Due) })
+	if len(due) > maxContextDue {
+		due = due[:maxContextDue]
+	}
+	return due
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDue(t *testing.T) {
+	// a wednesday
+	now := time.Date(2025, 3, 12, 10, 0, 0, 0, time.UTC)
+	at := func(month time.Month, day, hour, minute int) *time.Time {
+		t := time.Date(2025, month, day, hour, minute, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := []struct {
+		input string
+		want  *time.Time
+	}{
+		{"2025-03-14T17:00:00Z", at(3, 14, 17, 0)},
+		{"2025-03-14T17:00:00+01:00", at(3, 14, 16, 0)},
+		{"2025-03-14T09:30", at(3, 14, 9, 30)},
+		{"2025-03-14 09:30", at(3, 14, 9, 30)},
+		{"2025-03-14", at(3, 14, defaultDueHour, 0)},
+		{"tomorrow 5pm", at(3, 13, 17, 0)},
+		{"Tomorrow 9:15am", at(3, 13, 9, 15)},
+		{"+3d", at(3, 15, 10, 0)},
+		{"in 2 hours", at(3, 12, 12, 0)},
+		{"fri", at(3, 14, defaultDueHour, 0)},
+		{"wednesday", at(3, 19, defaultDueHour, 0)},
+		{"mar 20 9am", at(3, 20, 9, 0)},
+		{"9am", at(3, 13, 9, 0)},
+		{"none", nil},
+		{"clear", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := parseDue(tt.input, now)
+		if err != nil {
+			t.Errorf("parseDue(%q): %v", tt.input, err)
+			continue
+		}
+		switch {
+		case tt.want == nil && got != nil:
+			t.Errorf("parseDue(%q) = %v, want none", tt.input, got)
+		case tt.want != nil && (got == nil || !got.Equal(*tt.want)):
+			t.Errorf("parseDue(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseDueRejects(t *testing.T) {
+	now := time.Date(2025, 3, 12, 10, 0, 0, 0, time.UTC)
+	for _, input := range []string{"5", "tomorrow 5", "someday", "25:00", "2025-13-01T10:00:00Z"} {
+		if got, err := parseDue(input, now); err == nil {
+			t.Errorf("parseDue(%q) = %v, want an error", input, got)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// maxDescLength is trello's limit on a card description.
+const maxDescLength = 16384
+
+// descEditor is the full-pane description editor. ctrl+p flips between the
+// text and its rendered markdown.
+type descEditor struct {
+	visible  bool
+	card     board.Card
+	area     textarea.Model
+	preview  bool
+	rendered viewport.Model
+	width    int
+	height   int
+	// esc was pressed once with unsaved changes
+	discardArmed bool
+}
+
+func newDescEditor() descEditor {
+	ta := textarea.New()
+	ta.CharLimit = maxDescLength
+	ta.MaxHeight = 0
+	ta.ShowLineNumbers = false
+	ta.Placeholder = "describe the card in markdown..."
+	ta.Prompt = ""
+	return descEditor{area: ta, rendered: viewport.New(60, 10)}
+}
+
+func (e *descEditor) Open(card board.Card) tea.Cmd {
+	e.visible = true
+	e.card = card
+	e.preview = false
+	e.discardArmed = false
+	e.area.SetValue(card.Desc)
+	return e.area.Focus()
+}
+
+func (e *descEditor) Close() {
+	e.visible = false
+	e.area.Blur()
+}
+
+// Value is the edited description, without the trailing blank lines editors
+// like to leave.
+func (e *descEditor) Value() string {
+	return strings.TrimRight(e.area.Value(), " \t\n")
+}
+
+func (e *descEditor) Modified() bool {
+	return e.Value() != strings.TrimRight(e.card.Desc, " \t\n")
+}
+
+// SetValue replaces the text, e.g. with what $EDITOR saved.
+func (e *descEditor) SetValue(text string) {
+	e.area.SetValue(strings.TrimRight(text, " \t\n"))
+	e.discardArmed = false
+	if e.preview {
+		e.render()
+	}
+}
+
+func (e *descEditor) TogglePreview() {
+	e.preview = !e.preview
+	if e.preview {
+		e.render()
+		e.area.Blur()
+	} else {
+		e.area.Focus()
+	}
+}
+
+func (e *descEditor) Update(msg tea.KeyMsg) tea.Cmd {
+	e.discardArmed = false
+	if e.preview {
+		var cmd tea.Cmd
+		e.rendered, cmd = e.rendered.Update(msg)
+		return cmd
+	}
+	var cmd tea.Cmd
+	e.area, cmd = e.area.Update(msg)
+	return cmd
+}
+
+func (e *descEditor) Resize(w, h int) {
+	e.width = w
+	e.height = h
+	// inside the border and padding, less the title and key lines
+	inner := max(20, w-4)
+	rows := max(3, h-4)
+	e.area.SetWidth(inner)
+	e.area.SetHeight(rows)
+	e.rendered.Width = inner
+	e.rendered.Height = rows
+	if e.preview {
+		e.render()
+	}
+}
+
+func (e *descEditor) render() {
+	text := e.Value()
+	if text == "" {
+		e.rendered.SetContent(subtleStyle.Render("(no description)"))
+		return
+	}
+	e.rendered.SetContent(renderMarkdown(text, e.rendered.Width))
+}
+
+func (e *descEditor) View() string {
+	title := lipgloss.NewStyle().Bold(true).Render("Description: ") +
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("229")).Render(ellipsis(e.card.Name, max(10, e.width-24)))
+	mode := "edit"
+	body := e.area.View()
+	if e.preview {
+		mode = "preview"
+		body = e.rendered.View()
+	}
+	if e.Modified() {
+		mode += " · modified"
+	}
+	keys := "ctrl+s: save  ctrl+p: preview  ctrl+o: $EDITOR  esc: cancel"
+	if e.preview {
+		keys = "ctrl+s: save  ctrl+p: edit  j/k: scroll  ctrl+o: $EDITOR  esc: cancel"
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		title+"  "+subtleStyle.Render(mode),
+		"",
+		body,
+		"",
+		subtleStyle.Render(keys),
+	)
+	return drawerStyle.Width(max(20, e.width-2)).Height(e.height).Render(content)
+}
+
+var (
+	mdHeadingRe = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdBulletRe  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdTaskRe    = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	mdNumberRe  = regexp.MustCompile(`^(\s*)(\d+[.)])\s+(.*)$`)
+	mdRuleRe    = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	mdBoldRe    = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	mdItalicRe  = regexp.MustCompile(`\*([^*\s][^*]*?)\*|\b_([^_\s][^_]*?)_\b`)
+	mdCodeRe    = regexp.MustCompile("`([^`]+)`")
+	mdLinkRe    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+
+	mdHeadingStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("229"))
+	mdCodeStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("180"))
+	mdLinkStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("117")).Underline(true)
+)
+
+// renderMarkdown styles the markdown a card description usually holds:
+// headings, lists and task lists, quotes, rules, code and inline emphasis.
+// lines are wrapped to width before inline styles are applied, so emphasis
+// that spans a wrap is left as typed.
+func renderMarkdown(s string, width int) string {
+	var out []string
+	fenced := false
+	for _, line := range strings.Split(s, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			out = append(out, mdCodeStyle.Render("  "+ellipsis(line, max(10, width-2))))
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		prefix, text, style := "", trimmed, lipgloss.NewStyle()
+		inline := true
+		switch {
+		case trimmed == "":
+			out = append(out, "")
+			continue
+		case mdRuleRe.MatchString(trimmed):
+			out = append(out, subtleStyle.Render(strings.Repeat("─", max(10, width))))
+			continue
+		case mdHeadingRe.MatchString(trimmed):
+			m := mdHeadingRe.FindStringSubmatch(trimmed)
+			text, style, inline = stripMarkdown(m[2]), mdHeadingStyle, false
+		case strings.HasPrefix(trimmed, ">"):
+			prefix, text, style = "│ ", strings.TrimSpace(strings.TrimPrefix(trimmed, ">")), subtleStyle
+		case mdBulletRe.MatchString(line):
+			m := mdBulletRe.FindStringSubmatch(line)
+			indent := strings.Repeat("  ", len(m[1])/2)
+			prefix, text = indent+"• ", m[2]
+			if t := mdTaskRe.FindStringSubmatch(text); t != nil {
+				prefix, text = indent+"☐ ", t[2]
+				if t[1] != " " {
+					prefix, style = indent+"☑ ", subtleStyle
+				}
+			}
+		case mdNumberRe.MatchString(line):
+			m := mdNumberRe.FindStringSubmatch(line)
+			prefix, text = strings.Repeat("  ", len(m[1])/2)+m[2]+" ", m[3]
+		}
+
+		pad := strings.Repeat(" ", lipgloss.Width(prefix))
+		for i, part := range strings.Split(wrapForPane(text, max(10, width-lipgloss.Width(prefix))), "\n") {
+			if inline {
+				part = renderInline(part)
+			}
+			lead := pad
+			if i == 0 {
+				lead = prefix
+			}
+			out = append(out, lead+style.Render(part))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+func renderInline(s string) string {
+	s = mdCodeRe.ReplaceAllStringFunc(s, func(m string) string {
+		return mdCodeStyle.Render(mdCodeRe.FindStringSubmatch(m)[1])
+	})
+	s = mdLinkRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdLinkRe.FindStringSubmatch(m)
+		return mdLinkStyle.Render(sub[1]) + subtleStyle.Render(" ("+sub[2]+")")
+	})
+	s = mdBoldRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdBoldRe.FindStringSubmatch(m)
+		return lipgloss.NewStyle().Bold(true).Render(sub[1] + sub[2])
+	})
+	s = mdItalicRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdItalicRe.FindStringSubmatch(m)
+		return lipgloss.NewStyle().Italic(true).Render(sub[1] + sub[2])
+	})
+	return s
+}
+
+// editExternallyCmd suspends the ui to edit text in $VISUAL or $EDITOR (vi
+// when neither is set) on a temp file, reporting what was saved.
+func editExternallyCmd(cardID, text string) tea.Cmd {
+	f, err := os.CreateTemp("", "aboard-*.md")
+	if err != nil {
+		return func() tea.Msg { return externalEditedMsg{cardID: cardID, err: err} }
+	}
+	path := f.Name()
+	_, err = f.WriteString(text + "\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg { return externalEditedMsg{cardID: cardID, err: err} }
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// the variable may carry flags, e.g. "code --wait"
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return externalEditedMsg{cardID: cardID, err: err}
+		}
+		b, err := os.ReadFile(path)
+		return externalEditedMsg{cardID: cardID, text: string(b), err: err}
+	})
+}
@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// maxContextFiltered caps the matching cards listed in agent context while a
+// filter is active.
+const maxContextFiltered = 60
+
+// cardFilter narrows the board to the cards matching every term of an
+// expression like `label:bug @me due:overdue "login"`.
+type cardFilter struct {
+	terms []filterTerm
+}
+
+type filterTerm struct {
+	raw    string // as typed, to show and edit the expression again
+	kind   string // label, member, due or text
+	value  string
+	negate bool
+}
+
+var dueFilters = []string{"overdue", "soon", "week", "none", "any", "done"}
+
+// parseFilter reads a filter expression. terms are space-separated and all
+// have to match; quote a term to keep its spaces and a leading - negates it:
+//
+//	label:bug label:red label:"needs review"  cards with the label, by name or color
+//	@me @alice member:"Alice Smith"           cards assigned to the member
+//	due:overdue|soon|week|none|any|done       cards by due date
+//	login "sign in"                           text in the name or description
+//
+// labels and members are checked against the board so typos are reported.
+func parseFilter(expr string, k *KanbanModel) (cardFilter, error) {
+	var f cardFilter
+	for _, raw := range splitFilter(expr) {
+		t := filterTerm{raw: raw}
+		s := raw
+		if len(s) > 1 && s[0] == '-' {
+			t.negate = true
+			s = s[1:]
+		}
+		switch key, value, ok := strings.Cut(s, ":"); {
+		case strings.HasPrefix(s, "@"):
+			t.kind, t.value = "member", unquote(s[1:])
+		case ok && strings.EqualFold(key, "member"):
+			t.kind, t.value = "member", unquote(value)
+		case ok && strings.EqualFold(key, "label"):
+			t.kind, t.value = "label", unquote(value)
+		case ok && strings.EqualFold(key, "due"):
+			t.kind, t.value = "due", strings.ToLower(unquote(value))
+		default:
+			t.kind, t.value = "text", strings.ToLower(unquote(s))
+		}
+
+		if t.value == "" {
+			return cardFilter{}, fmt.Errorf("%q needs a value", raw)
+		}
+		switch t.kind {
+		case "member":
+			if k.findMember(t.value) == nil {
+				return cardFilter{}, fmt.Errorf("no member %q on this board", t.value)
+			}
+		case "label":
+			if !slices.ContainsFunc(k.labels, func(l board.Label) bool { return labelMatches(l, t.value) }) {
+				return cardFilter{}, fmt.Errorf("no label %q on this board", t.value)
+			}
+		case "due":
+			if !slices.Contains(dueFilters, t.value) {
+				return cardFilter{}, fmt.Errorf("due: takes %s", strings.Join(dueFilters, ", "))
+			}
+		}
+		f.terms = append(f.terms, t)
+	}
+	return f, nil
+}
+
+// splitFilter splits an expression on spaces outside double quotes, keeping
+// the quotes.
+func splitFilter(expr string) []string {
+	var terms []string
+	var b strings.Builder
+	quoted := false
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			b.WriteRune(r)
+		case r == ' ' && !quoted:
+			if b.Len() > 0 {
+				terms = append(terms, b.String())
+				b.Reset()
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() > 0 {
+		terms = append(terms, b.String())
+	}
+	return terms
+}
+
+func unquote(s string) string {
+	return strings.TrimSpace(strings.Trim(s, `"`))
+}
+
+func labelMatches(l board.Label, ref string) bool {
+	return strings.EqualFold(l.Name, ref) || strings.EqualFold(l.Color, ref)
+}
+
+func (f cardFilter) Active() bool {
+	return len(f.terms) > 0
+}
+
+// String is the expression the filter was parsed from.
+func (f cardFilter) String() string {
+	raws := make([]string, len(f.terms))
+	for i, t := range f.terms {
+		raws[i] = t.raw
+	}
+	return strings.Join(raws, " ")
+}
+
+// memberTerm returns the index of a term selecting the member with id, or -1.
+func (f cardFilter) memberTerm(k *KanbanModel, id string) int {
+	return slices.IndexFunc(f.terms, func(t filterTerm) bool {
+		if t.kind != "member" || t.negate {
+			return false
+		}
+		m := k.findMember(t.value)
+		return m != nil && m.ID == id
+	})
+}
+
+func (k *KanbanModel) matchTerm(c board.Card, t filterTerm, now time.Time) bool {
+	switch t.kind {
+	case "member":
+		m := k.findMember(t.value)
+		return m != nil && slices.Contains(c.MemberIDs, m.ID)
+	case "label":
+		return slices.ContainsFunc(c.Labels, func(l board.Label) bool { return labelMatches(l, t.value) })
+	case "due":
+		open := c.Due != nil && !c.DueComplete
+		switch t.value {
+		case "overdue":
+			return open && c.Due.Before(now)
+		case "soon":
+			return open && !c.Due.Before(now) && c.Due.Sub(now) < 24*time.Hour
+		case "week":
+			return open && !c.Due.Before(now) && c.Due.Sub(now) < 7*24*time.Hour
+		case "none":
+			return c.Due == nil
+		case "any":
+			return c.Due != nil
+		case "done":
+			return c.Due != nil && c.DueComplete
+		}
+		return false
+	default:
+		return strings.Contains(strings.ToLower(c.Name), t.value) || strings.Contains(strings.ToLower(c.Desc), t.value)
+	}
+}
+
+// filteredCards lists the cards the filter shows, in board order, for agent
+// context.
+func (k *KanbanModel) filteredCards() []board.Card {
+	var cards []board.Card
+	for _, l := range k.lists {
+		for _, c := range k.cards[l.ID] {
+			if k.shown(c) {
+				cards = append(cards, c)
+			}
+		}
+	}
+	return cards
+}
+
+func (k *KanbanModel) cardCount() int {
+	n := 0
+	for _, cards := range k.cards {
+		n += len(cards)
+	}
+	return n
+}
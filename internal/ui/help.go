@@ -20,33 +20,78 @@ func (h *HelpModel) Render(bg string, w, ht int) string {
 		"  enter       open card in drawer",
 		"  m           move card (list picker)",
 		"  e           rename card",
+		"  E           edit description",
 		"  c           comment on card",
 		"  n           new card in current list",
 		"  N           new list on board",
 		"  x           archive card (confirm)",
 		"  X           archive list (confirm)",
+		"  L           edit card labels",
+		"  M           edit card members",
+		"  space       join / leave card",
+		"  f           filter cards (label:bug @me due:overdue \"text\")",
+		"  F           add / remove @me in the filter",
+		"  d           set due date",
+		"  D           toggle due date done",
+		"  u           undo last change",
+		"  U           undo history",
 		"  / or tab    focus prompt bar",
-		"  1/2/a       agent controls",
+		"  1-9         pick agent profile",
+		"  a           cycle agent profiles",
 		"  r           refresh board data",
 		"  b           board selector",
 		"  q           quit",
 		"",
 		"Prompt",
 		"  enter       submit (context-dependent)",
+		"  /agent name switch agent profile",
 		"  esc         cancel, return to kanban",
 		"  h/l         navigate list picker (move)",
+		"  space       toggle label (label picker)",
+		"  due         tomorrow 5pm, +3d, fri, none",
+		"  filter      label:x @member due:overdue|soon|week|none|any|done",
+		"              \"text\", -term negates, empty clears",
 		"",
 		"Drawer",
 		"  j/k         scroll timeline",
+		"  esc esc     cancel running agent",
 		"  tab         focus kanban",
 		"  /           focus prompt",
-		"  m/e/c/x     card operations",
+		"  m/e/E/c/x/L/M/d card operations",
+		"  J/K         move through checklist items",
+		"  space       check / uncheck item",
+		"  i           add checklist item",
+		"  T           turn item into a card",
+		"  u/U         undo / undo history",
+		"",
+		"Search",
+		"  type        fuzzy match names, descriptions, labels, ids",
+		"  ↑/↓         move through results",
+		"  tab         this board / all boards (trello: label:, @me, due:)",
+		"  enter       jump to card (loads its board)",
+		"  esc         close",
+		"",
+		"Description editor",
+		"  ctrl+s      save",
+		"  ctrl+p      preview markdown / back to editing",
+		"  ctrl+o      edit in $VISUAL or $EDITOR",
+		"  esc         cancel (twice with unsaved changes)",
+		"",
+		"Action review",
+		"  j/k         move through actions",
+		"  y/n         approve / reject",
+		"  Y/N         approve / reject all",
+		"  e           edit action",
+		"  enter       run approved actions",
+		"  esc         reject all",
 		"",
 		"Global",
 		"  ctrl+c      quit",
-		"  ctrl+a      toggle agent",
+		"  ctrl+a      cycle agent",
+		"  ctrl+x      cancel running agent",
 		"  ctrl+r      refresh",
 		"  ctrl+b      board selector",
+		"  ctrl+f      search cards (all boards from the board selector)",
 		"  ?           toggle help",
 		"",
 		"Press ? or Esc to close.",
@@ -3,32 +3,187 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/codywilliamson/aboard/internal/trello"
+	"github.com/codywilliamson/aboard/internal/board"
 )
 
 type KanbanModel struct {
-	lists        []trello.List
-	cards        map[string][]trello.Card
+	lists        []board.List
+	cards        map[string][]board.Card
 	listCursor   int
 	cardCursors  map[string]int
 	scrollOffset int
-	contextCard  *trello.Card
+	contextCard  *board.Card
 	width        int
 	height       int
+
+	// the board's labels, for the picker and agent actions
+	labels []board.Label
+	// checklists of cards opened in the drawer, by card id
+	checklists map[string][]board.Checklist
+	// the board's members, and which of them the provider is signed in as
+	members []board.Member
+	me      string
+	// when active, only the cards matching it are shown
+	filter cardFilter
+
+	// cards a background poll changed that haven't been selected since
+	changed map[string]bool
+	// bumped by every optimistic change, so a poll that raced one is dropped
+	edits int
 }
 
-func (k *KanbanModel) SetData(lists []trello.List, cards []trello.Card) {
-	k.lists = lists
-	k.cards = make(map[string][]trello.Card, len(lists))
-	for _, card := range cards {
-		k.cards[card.IDList] = append(k.cards[card.IDList], card)
-	}
+func (k *KanbanModel) SetData(lists []board.List, cards []board.Card) {
+	k.setCards(lists, cards)
 	k.listCursor = 0
 	k.scrollOffset = 0
 	k.cardCursors = make(map[string]int, len(lists))
 	k.contextCard = nil
+	k.changed = nil
+	k.checklists = nil
+}
+
+// Refresh swaps in fresh data for the same board, keeping the selected list
+// and cards by id. the selected card is followed if it moved to another list;
+// a cursor only falls back to its old position when its card is gone.
+func (k *KanbanModel) Refresh(lists []board.List, cards []board.Card) {
+	prevList := k.activeListID()
+	prevSelected := ""
+	if c := k.SelectedCard(); c != nil {
+		prevSelected = c.ID
+	}
+	prevCursor := make(map[string]string, len(k.lists))
+	for _, l := range k.lists {
+		if i := k.cardCursors[l.ID]; i >= 0 && i < len(k.cards[l.ID]) {
+			prevCursor[l.ID] = k.cards[l.ID][i].ID
+		}
+	}
+	prevIndex := k.cardCursors
+
+	k.setCards(lists, cards)
+
+	k.cardCursors = make(map[string]int, len(lists))
+	for _, l := range lists {
+		cursor := prevIndex[l.ID]
+		if i := cardIndex(k.cards[l.ID], prevCursor[l.ID]); i >= 0 {
+			cursor = i
+		}
+		k.cardCursors[l.ID] = max(0, min(cursor, len(k.cards[l.ID])-1))
+	}
+
+	k.listCursor = max(0, min(k.listCursor, len(lists)-1))
+	if i := k.listIndex(prevList); i >= 0 {
+		k.listCursor = i
+	}
+	if c := k.findCard(prevSelected); c != nil {
+		if i := k.listIndex(c.IDList); i >= 0 {
+			k.listCursor = i
+			k.cardCursors[c.IDList] = cardIndex(k.cards[c.IDList], c.ID)
+		}
+	}
+
+	if k.contextCard != nil {
+		k.contextCard = k.findCard(k.contextCard.ID)
+	}
+	k.scrollOffset = max(0, min(k.scrollOffset, len(lists)-1))
+	k.ensureHorizontalScroll()
+}
+
+// Patch applies an incremental sync on top of the loaded board.
+func (k *KanbanModel) Patch(ch *board.Changes) {
+	lists := k.lists
+	if ch.Lists != nil {
+		lists = ch.Lists
+	}
+	changed := make(map[string]board.Card, len(ch.Cards))
+	for _, c := range ch.Cards {
+		changed[c.ID] = c
+	}
+	removed := make(map[string]bool, len(ch.Removed))
+	for _, id := range ch.Removed {
+		removed[id] = true
+	}
+
+	var cards []board.Card
+	for _, l := range k.lists {
+		for _, c := range k.cards[l.ID] {
+			if removed[c.ID] {
+				continue
+			}
+			if fresh, ok := changed[c.ID]; ok {
+				// cards that changed list go to the bottom of their new one
+				if fresh.IDList != c.IDList {
+					continue
+				}
+				c = fresh
+				delete(changed, c.ID)
+			}
+			cards = append(cards, c)
+		}
+	}
+	for _, c := range ch.Cards {
+		if _, ok := changed[c.ID]; ok {
+			cards = append(cards, c)
+		}
+	}
+	k.Refresh(lists, cards)
+	// list names may have changed underneath the cards
+	for id, cs := range k.cards {
+		if l := k.findList(id); l != nil {
+			for i := range cs {
+				cs[i].ListName = l.Name
+			}
+		}
+	}
+}
+
+func (k *KanbanModel) setCards(lists []board.List, cards []board.Card) {
+	k.lists = lists
+	k.cards = make(map[string][]board.Card, len(lists))
+	for _, card := range cards {
+		k.cards[card.IDList] = append(k.cards[card.IDList], card)
+	}
+}
+
+func (k *KanbanModel) listIndex(id string) int {
+	for i, l := range k.lists {
+		if l.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func cardIndex(cards []board.Card, id string) int {
+	for i, c := range cards {
+		if c.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// findCard returns a copy of the card with id, or nil if it isn't loaded.
+func (k *KanbanModel) findCard(id string) *board.Card {
+	for _, cards := range k.cards {
+		for _, c := range cards {
+			if c.ID == id {
+				return &c
+			}
+		}
+	}
+	return nil
+}
+
+func (k *KanbanModel) findList(id string) *board.List {
+	for _, l := range k.lists {
+		if l.ID == id {
+			return &l
+		}
+	}
+	return nil
 }
 
 func (k *KanbanModel) activeListID() string {
@@ -38,7 +193,7 @@ func (k *KanbanModel) activeListID() string {
 	return k.lists[k.listCursor].ID
 }
 
-func (k *KanbanModel) ActiveList() *trello.List {
+func (k *KanbanModel) ActiveList() *board.List {
 	if k.listCursor < 0 || k.listCursor >= len(k.lists) {
 		return nil
 	}
@@ -46,20 +201,49 @@ func (k *KanbanModel) ActiveList() *trello.List {
 	return &l
 }
 
-func (k *KanbanModel) SelectedCard() *trello.Card {
+func (k *KanbanModel) SelectedCard() *board.Card {
 	id := k.activeListID()
 	if id == "" {
 		return nil
 	}
-	cards := k.cards[id]
-	cursor := k.cardCursors[id]
-	if cursor < 0 || cursor >= len(cards) {
+	i := k.cursorIndex(id)
+	if i < 0 {
 		return nil
 	}
-	c := cards[cursor]
+	c := k.cards[id][i]
 	return &c
 }
 
+// shown reports whether a card passes the board filter.
+func (k *KanbanModel) shown(c board.Card) bool {
+	now := time.Now()
+	for _, t := range k.filter.terms {
+		if k.matchTerm(c, t, now) == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// cursorIndex is the index of the list's selected card: the one under the
+// cursor, or the nearest shown card when the filter hides it. -1 when the
+// list shows no cards.
+func (k *KanbanModel) cursorIndex(listID string) int {
+	cards := k.cards[listID]
+	cursor := max(0, min(k.cardCursors[listID], len(cards)-1))
+	for i := cursor; i < len(cards); i++ {
+		if k.shown(cards[i]) {
+			return i
+		}
+	}
+	for i := cursor - 1; i >= 0; i-- {
+		if k.shown(cards[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
 func (k *KanbanModel) MovePrevList() {
 	if k.listCursor > 0 {
 		k.listCursor--
@@ -79,8 +263,12 @@ func (k *KanbanModel) MovePrevCard() {
 	if id == "" {
 		return
 	}
-	if k.cardCursors[id] > 0 {
-		k.cardCursors[id]--
+	cards := k.cards[id]
+	for i := k.cursorIndex(id) - 1; i >= 0; i-- {
+		if k.shown(cards[i]) {
+			k.cardCursors[id] = i
+			return
+		}
 	}
 }
 
@@ -89,8 +277,14 @@ func (k *KanbanModel) MoveNextCard() {
 	if id == "" {
 		return
 	}
-	if k.cardCursors[id] < len(k.cards[id])-1 {
-		k.cardCursors[id]++
+	cards := k.cards[id]
+	if from := k.cursorIndex(id); from >= 0 {
+		for i := from + 1; i < len(cards); i++ {
+			if k.shown(cards[i]) {
+				k.cardCursors[id] = i
+				return
+			}
+		}
 	}
 }
 
@@ -139,16 +333,32 @@ func (k *KanbanModel) View() string {
 	return board
 }
 
-func (k *KanbanModel) renderColumn(list trello.List, width, height int, active bool) string {
-	cards := k.cards[list.ID]
-	cursor := k.cardCursors[list.ID]
+func (k *KanbanModel) renderColumn(list board.List, width, height int, active bool) string {
+	all := k.cards[list.ID]
+	// the cursor indexes every card; find it among the shown ones
+	selected := k.cursorIndex(list.ID)
+	cursor := -1
+	var cards []board.Card
+	for i, c := range all {
+		if !k.shown(c) {
+			continue
+		}
+		if i == selected {
+			cursor = len(cards)
+		}
+		cards = append(cards, c)
+	}
 
 	header := lipgloss.NewStyle().Bold(true).Render(ellipsis(list.Name, width-2))
 	noun := "cards"
-	if len(cards) == 1 {
+	if len(all) == 1 {
 		noun = "card"
 	}
-	countStr := subtleStyle.Render(fmt.Sprintf("%d %s", len(cards), noun))
+	count := fmt.Sprintf("%d %s", len(all), noun)
+	if k.filter.Active() {
+		count = fmt.Sprintf("%d/%d %s", len(cards), len(all), noun)
+	}
+	countStr := subtleStyle.Render(count)
 
 	// visible card range — ensure cursor stays in view
 	maxCards := max(1, height-4)
@@ -158,6 +368,7 @@ func (k *KanbanModel) renderColumn(list trello.List, width, height int, active b
 	}
 	endIdx := min(startIdx+maxCards, len(cards))
 
+	now := time.Now()
 	var lines []string
 	for i := startIdx; i < endIdx; i++ {
 		card := cards[i]
@@ -167,18 +378,30 @@ func (k *KanbanModel) renderColumn(list trello.List, width, height int, active b
 		}
 
 		isContext := k.contextCard != nil && k.contextCard.ID == card.ID
+		isChanged := k.changed[card.ID]
 		if isContext {
 			prefix = string(prefix[0:1]) + "◆"
+		} else if isChanged {
+			prefix = string(prefix[0:1]) + "•"
 		}
 
-		name := ellipsis(card.Name, width-4)
+		dots := labelDots(card.Labels) + checkProgress(card) + k.memberBadges(card.MemberIDs)
+		if card.Due != nil {
+			dots += " " + dueStyle(*card.Due, card.DueComplete, now).Render(dueText(*card.Due, card.DueComplete, now))
+		}
+		name := ellipsis(card.Name, width-4-lipgloss.Width(dots))
 		line := prefix + name
 
 		if active && i == cursor {
 			line = selectedRowStyle.Render(line)
 		} else if isContext {
 			line = contextMarkerStyle.Render(line)
+		} else if isChanged {
+			line = changedCardStyle.Render(line)
+		} else if isPending(card.ID) {
+			line = subtleStyle.Render(line)
 		}
+		line += dots
 		lines = append(lines, line)
 	}
 
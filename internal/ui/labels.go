@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// maxLabelDots caps the markers on a card row so the name keeps some room.
+const maxLabelDots = 4
+
+// labelColors maps provider color names onto the 256-color palette. trello's
+// _light and _dark variants share their base color.
+var labelColors = map[string]lipgloss.Color{
+	"green":  "71",
+	"yellow": "221",
+	"orange": "208",
+	"red":    "203",
+	"purple": "141",
+	"blue":   "75",
+	"sky":    "117",
+	"lime":   "155",
+	"pink":   "211",
+	"black":  "245",
+}
+
+func labelColor(l board.Label) lipgloss.Color {
+	base, _, _ := strings.Cut(l.Color, "_")
+	if c, ok := labelColors[base]; ok {
+		return c
+	}
+	return "246"
+}
+
+// labelName falls back to the color for unnamed labels, as trello does.
+func labelName(l board.Label) string {
+	switch {
+	case l.Name != "":
+		return l.Name
+	case l.Color != "":
+		return l.Color
+	default:
+		return "label " + shortID(l.ID)
+	}
+}
+
+// labelDots renders a card's labels as colored markers, with a leading space
+// so it can be appended to the row.
+func labelDots(labels []board.Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(" ")
+	for i, l := range labels {
+		if i == maxLabelDots {
+			b.WriteString(subtleStyle.Render("+"))
+			break
+		}
+		b.WriteString(lipgloss.NewStyle().Foreground(labelColor(l)).Render("●"))
+	}
+	return b.String()
+}
+
+// labelList renders label names in their colors for the drawer.
+func labelList(labels []board.Label) string {
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = lipgloss.NewStyle().Foreground(labelColor(l)).Render("● " + labelName(l))
+	}
+	return strings.Join(names, "  ")
+}
+
+func labelIndex(labels []board.Label, id string) int {
+	for i, l := range labels {
+		if l.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// findLabel resolves a board label by id, or by name or color as an agent
+// might refer to it.
+func (k *KanbanModel) findLabel(ref string) *board.Label {
+	if i := labelIndex(k.labels, ref); i >= 0 {
+		return &k.labels[i]
+	}
+	for _, match := range []func(board.Label) bool{
+		func(l board.Label) bool { return strings.EqualFold(l.Name, ref) },
+		func(l board.Label) bool { return l.Name == "" && strings.EqualFold(l.Color, ref) },
+	} {
+		for i := range k.labels {
+			if match(k.labels[i]) {
+				return &k.labels[i]
+			}
+		}
+	}
+	return nil
+}
+
+// labelRef describes the label an action names, for the review queue.
+func (k *KanbanModel) labelRef(ref string) string {
+	if l := k.findLabel(ref); l != nil {
+		return labelName(*l)
+	}
+	if ref == "" {
+		return "(no label)"
+	}
+	return fmt.Sprintf("%q (not on board)", ref)
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// maxMemberBadges caps the initials on a card row.
+const maxMemberBadges = 2
+
+func memberName(m board.Member) string {
+	switch {
+	case m.FullName != "":
+		return m.FullName
+	case m.Username != "":
+		return m.Username
+	default:
+		return "member " + shortID(m.ID)
+	}
+}
+
+// memberInitials is what trello puts on its avatars: the first letters of
+// the first two words of the name.
+func memberInitials(m board.Member) string {
+	var initials []rune
+	for _, w := range strings.Fields(memberName(m)) {
+		initials = append(initials, unicode.ToUpper([]rune(w)[0]))
+		if len(initials) == 2 {
+			break
+		}
+	}
+	return string(initials)
+}
+
+func (k *KanbanModel) member(id string) board.Member {
+	for _, m := range k.members {
+		if m.ID == id {
+			return m
+		}
+	}
+	return board.Member{ID: id}
+}
+
+// memberBadges renders a card's members as initials, with a leading space
+// so it can be appended to the row.
+func (k *KanbanModel) memberBadges(ids []string) string {
+	if len(ids) == 0 {
+		return ""
+	}
+	var parts []string
+	for i, id := range ids {
+		if i == maxMemberBadges {
+			parts = append(parts, fmt.Sprintf("+%d", len(ids)-i))
+			break
+		}
+		parts = append(parts, memberInitials(k.member(id)))
+	}
+	return " " + memberStyle.Render(strings.Join(parts, " "))
+}
+
+// memberList names a card's members for the drawer and agent context.
+func (k *KanbanModel) memberList(ids []string) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		m := k.member(id)
+		names[i] = memberName(m)
+		if m.Username != "" && m.Username != names[i] {
+			names[i] += " (@" + m.Username + ")"
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// findMember resolves a board member by id, "me", @username or full name,
+// as an agent might refer to them.
+func (k *KanbanModel) findMember(ref string) *board.Member {
+	if strings.EqualFold(ref, "me") && k.me != "" {
+		ref = k.me
+	}
+	ref = strings.TrimPrefix(ref, "@")
+	for _, match := range []func(board.Member) bool{
+		func(m board.Member) bool { return m.ID == ref },
+		func(m board.Member) bool { return strings.EqualFold(m.Username, ref) },
+		func(m board.Member) bool { return strings.EqualFold(m.FullName, ref) },
+	} {
+		for i := range k.members {
+			if match(k.members[i]) {
+				return &k.members[i]
+			}
+		}
+	}
+	return nil
+}
+
+// memberRef describes the member an action names, for the review queue.
+func (k *KanbanModel) memberRef(ref string) string {
+	if m := k.findMember(ref); m != nil {
+		return memberName(*m)
+	}
+	if ref == "" {
+		return "(no member)"
+	}
+	return fmt.Sprintf("%q (not on board)", ref)
+}
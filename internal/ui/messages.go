@@ -2,24 +2,43 @@ package ui
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/codywilliamson/aboard/internal/agent"
-	"github.com/codywilliamson/aboard/internal/trello"
+	"github.com/codywilliamson/aboard/internal/board"
 )
 
 type boardsLoadedMsg struct {
-	boards []trello.Board
+	boards []board.Board
 	err    error
 }
 
+// cursor is the provider's sync position taken before the load, empty when
+// it can't sync incrementally. poll is set for background reloads.
 type boardDataLoadedMsg struct {
 	boardID   string
 	boardName string
-	lists     []trello.List
-	cards     []trello.Card
+	lists     []board.List
+	cards     []board.Card
+	cursor    string
+	poll      bool
 	err       error
+
+	// labels, members and me are only loaded with a new board
+	meta    bool
+	labels  []board.Label
+	members []board.Member
+	me      string
+}
+
+type boardSyncedMsg struct {
+	boardID string
+	changes *board.Changes
+	poll    bool
+	err     error
 }
 
 type agentResponseMsg struct {
@@ -29,19 +48,94 @@ type agentResponseMsg struct {
 	err    error
 }
 
+// agentChunkMsg carries streamed stdout from a running agent.
+type agentChunkMsg struct {
+	agent  agent.AgentName
+	prompt string
+	text   string
+	events <-chan agent.Event
+}
+
+// undo is set when the mutation succeeded and can be reversed; pending is
+// the optimistic local change it confirms or rolls back.
 type cardMutatedMsg struct {
-	action string
-	cardID string
-	err    error
+	action  string
+	cardID  string
+	undo    *undoEntry
+	pending *pendingChange
+	err     error
 }
 
 type listMutatedMsg struct {
-	action string
-	listID string
+	action  string
+	listID  string
+	undo    *undoEntry
+	pending *pendingChange
+	err     error
+}
+
+type undoneMsg struct {
+	entry undoEntry
+	err   error
+}
+
+// undoRequestMsg is sent by the undo agent action.
+type undoRequestMsg struct{}
+
+// watchStartedMsg reports whether the provider started pushing changes for a
+// board.
+type watchStartedMsg struct {
+	boardID string
+	events  <-chan board.Event
+	err     error
+}
+
+type boardEventMsg struct {
+	event  board.Event
+	events <-chan board.Event
+}
+
+type activityLoadedMsg struct {
+	cardID   string
+	activity []board.Activity
+	err      error
+}
+
+// externalEditedMsg carries what $EDITOR saved for a card's description.
+type externalEditedMsg struct {
+	cardID string
+	text   string
 	err    error
 }
 
-func loadBoardsCmd(client trelloClient) tea.Cmd {
+// searchDueMsg fires once typing in the search of every board pauses; seq
+// says which query it was for.
+type searchDueMsg struct {
+	seq int
+}
+
+type searchResultsMsg struct {
+	seq   int
+	found *board.SearchResults
+	err   error
+}
+
+type checklistsLoadedMsg struct {
+	cardID     string
+	checklists []board.Checklist
+	err        error
+}
+
+func searchBoardsCmd(s board.Searcher, query string, seq int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
+		defer cancel()
+		found, err := s.Search(ctx, query)
+		return searchResultsMsg{seq: seq, found: found, err: err}
+	}
+}
+
+func loadBoardsCmd(client board.BoardProvider) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
 		defer cancel()
@@ -50,97 +144,568 @@ func loadBoardsCmd(client trelloClient) tea.Cmd {
 	}
 }
 
-func loadBoardDataCmd(client trelloClient, boardID, boardName string) tea.Cmd {
+// loadBoardDataCmd loads the board's lists and cards, and with meta its
+// labels and members too, which a refresh of the same board keeps.
+func loadBoardDataCmd(client board.BoardProvider, boardID, boardName string, meta bool) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
-		lists, err := client.ListsForBoard(ctx, boardID)
-		if err != nil {
-			return boardDataLoadedMsg{boardID: boardID, boardName: boardName, err: err}
+		msg := boardDataLoadedMsg{boardID: boardID, boardName: boardName}
+		if s, ok := client.(board.Syncer); ok {
+			ch, err := s.Load(ctx, boardID)
+			if err != nil {
+				msg.err = err
+				return msg
+			}
+			msg.lists, msg.cards, msg.cursor = ch.Lists, ch.Cards, ch.Cursor
+		} else {
+			if msg.lists, msg.err = client.Lists(ctx, boardID); msg.err != nil {
+				return msg
+			}
+			if msg.cards, msg.err = client.Cards(ctx, boardID); msg.err != nil {
+				return msg
+			}
+		}
+		if !meta {
+			return msg
+		}
+		msg.meta = true
+		// the board is still usable without its label list, just not editable
+		if l, ok := client.(board.Labeler); ok {
+			msg.labels, _ = l.Labels(ctx, boardID)
+		}
+		// likewise members; cards then show ids the picker can't name
+		if a, ok := client.(board.Assigner); ok {
+			msg.members, _ = a.Members(ctx, boardID)
+			if m, err := a.Me(ctx); err == nil {
+				msg.me = m.ID
+			}
+		}
+		return msg
+	}
+}
+
+func syncBoardCmd(s board.Syncer, boardID, since string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		changes, err := s.Changes(ctx, boardID, since)
+		return boardSyncedMsg{boardID: boardID, changes: changes, err: err}
+	}
+}
+
+func watchBoardCmd(ctx context.Context, w board.Watcher, boardID string) tea.Cmd {
+	return func() tea.Msg {
+		events, err := w.Watch(ctx, boardID)
+		return watchStartedMsg{boardID: boardID, events: events, err: err}
+	}
+}
+
+// waitBoardEventCmd reads the next pushed change; it goes quiet once the
+// watch is stopped.
+func waitBoardEventCmd(events <-chan board.Event) tea.Cmd {
+	return func() tea.Msg {
+		ev, ok := <-events
+		if !ok {
+			return nil
 		}
-		cards, err := client.CardsForBoard(ctx, boardID)
+		return boardEventMsg{event: ev, events: events}
+	}
+}
+
+func askAgentCmd(ctx context.Context, runner agentRunner, active agent.AgentName, cardContext, prompt string) tea.Cmd {
+	return func() tea.Msg {
+		events, err := runner.Stream(ctx, active, cardContext, prompt)
 		if err != nil {
-			return boardDataLoadedMsg{boardID: boardID, boardName: boardName, err: err}
+			return agentResponseMsg{agent: active, prompt: prompt, err: err}
 		}
-		return boardDataLoadedMsg{boardID: boardID, boardName: boardName, lists: lists, cards: cards}
+		return nextAgentEvent(active, prompt, events)
 	}
 }
 
-func askAgentCmd(runner agentRunner, active agent.AgentName, cardContext, prompt string) tea.Cmd {
+// waitAgentCmd reads the next event of a running agent stream.
+func waitAgentCmd(active agent.AgentName, prompt string, events <-chan agent.Event) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-		output, err := runner.Ask(ctx, active, cardContext, prompt)
-		return agentResponseMsg{agent: active, prompt: prompt, output: output, err: err}
+		return nextAgentEvent(active, prompt, events)
+	}
+}
+
+func nextAgentEvent(active agent.AgentName, prompt string, events <-chan agent.Event) tea.Msg {
+	ev, ok := <-events
+	if !ok {
+		return agentResponseMsg{agent: active, prompt: prompt, err: errors.New("agent stream closed")}
+	}
+	if ev.Done {
+		return agentResponseMsg{agent: active, prompt: prompt, output: ev.Output, err: ev.Err}
 	}
+	return agentChunkMsg{agent: active, prompt: prompt, text: ev.Text, events: events}
 }
 
-func moveCardCmd(client trelloClient, cardID, listID string) tea.Cmd {
+// the card and list mutations take the item as it was before the change
+// (nil if it isn't on the loaded board) so they can be undone.
+
+func moveCardCmd(client board.BoardProvider, cardID, listID string, prev *board.Card) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		err := client.MoveCard(ctx, cardID, listID)
-		return cardMutatedMsg{action: "move", cardID: cardID, err: err}
+		msg := cardMutatedMsg{action: "move", cardID: cardID, err: err}
+		if err == nil && prev != nil && prev.IDList != listID {
+			msg.undo = newUndo("move", fmt.Sprintf("moved %q out of %s", prev.Name, prev.ListName))
+			msg.undo.cardID = cardID
+			msg.undo.listID = prev.IDList
+		}
+		return msg
 	}
 }
 
-func updateCardCmd(client trelloClient, cardID, name, desc string) tea.Cmd {
+func updateCardCmd(client board.BoardProvider, cardID string, fields board.CardUpdate, prev *board.Card) tea.Cmd {
 	return func() tea.Msg {
+		action := "update"
+		if fields.Name != nil && fields.Desc == nil {
+			action = "rename"
+		}
+		// still reply so the pending edit is settled
+		if fields.Empty() {
+			return cardMutatedMsg{action: action, cardID: cardID}
+		}
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-		err := client.UpdateCard(ctx, cardID, name, desc)
-		return cardMutatedMsg{action: "rename", cardID: cardID, err: err}
+		err := client.UpdateCard(ctx, cardID, fields)
+		msg := cardMutatedMsg{action: action, cardID: cardID, err: err}
+		if err == nil && prev != nil {
+			label := fmt.Sprintf("edited %q", prev.Name)
+			if action == "rename" {
+				label = fmt.Sprintf("renamed %q to %q", prev.Name, *fields.Name)
+			}
+			msg.undo = newUndo(action, label)
+			msg.undo.cardID = cardID
+			if fields.Name != nil {
+				msg.undo.fields.Name = &prev.Name
+			}
+			if fields.Desc != nil {
+				msg.undo.fields.Desc = &prev.Desc
+			}
+		}
+		return msg
 	}
 }
 
-func addCommentCmd(client trelloClient, cardID, text string) tea.Cmd {
+// addCommentCmd records an undo entry when the provider can delete the
+// comment again.
+func addCommentCmd(client board.BoardProvider, cardID, text string, prev *board.Card) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
-		err := client.AddComment(ctx, cardID, text)
-		return cardMutatedMsg{action: "comment", cardID: cardID, err: err}
+		d, ok := client.(board.CommentDeleter)
+		if !ok {
+			err := client.AddComment(ctx, cardID, text)
+			return cardMutatedMsg{action: "comment", cardID: cardID, err: err}
+		}
+		id, err := d.PostComment(ctx, cardID, text)
+		msg := cardMutatedMsg{action: "comment", cardID: cardID, err: err}
+		if err == nil && id != "" {
+			name := shortID(cardID)
+			if prev != nil {
+				name = prev.Name
+			}
+			msg.undo = newUndo("comment", fmt.Sprintf("commented on %q", name))
+			msg.undo.cardID = cardID
+			msg.undo.commentID = id
+		}
+		return msg
 	}
 }
 
-func archiveCardCmd(client trelloClient, cardID string) tea.Cmd {
+func archiveCardCmd(client board.BoardProvider, cardID string, prev *board.Card) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		err := client.ArchiveCard(ctx, cardID)
-		return cardMutatedMsg{action: "archive", cardID: cardID, err: err}
+		msg := cardMutatedMsg{action: "archive", cardID: cardID, err: err}
+		if err == nil {
+			name := shortID(cardID)
+			if prev != nil {
+				name = prev.Name
+			}
+			msg.undo = newUndo("archive", fmt.Sprintf("archived %q", name))
+			msg.undo.cardID = cardID
+		}
+		return msg
 	}
 }
 
-func createCardCmd(client trelloClient, listID, name string) tea.Cmd {
+func createCardCmd(client board.BoardProvider, listID, name string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		card, err := client.CreateCard(ctx, listID, name)
-		id := ""
-		if card != nil {
-			id = card.ID
+		msg := cardMutatedMsg{action: "create", err: err}
+		if err == nil && card != nil {
+			msg.cardID = card.ID
+			msg.undo = newUndo("create", fmt.Sprintf("created %q", name))
+			msg.undo.cardID = card.ID
 		}
-		return cardMutatedMsg{action: "create", cardID: id, err: err}
+		return msg
 	}
 }
 
-func createListCmd(client trelloClient, boardID, name string) tea.Cmd {
+func createListCmd(client board.BoardProvider, boardID, name string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		list, err := client.CreateList(ctx, boardID, name)
-		id := ""
-		if list != nil {
-			id = list.ID
+		msg := listMutatedMsg{action: "create", err: err}
+		if err == nil && list != nil {
+			msg.listID = list.ID
+			msg.undo = newUndo("create list", fmt.Sprintf("created list %q", name))
+			msg.undo.listID = list.ID
 		}
-		return listMutatedMsg{action: "create", listID: id, err: err}
+		return msg
 	}
 }
 
-func archiveListCmd(client trelloClient, listID string) tea.Cmd {
+func archiveListCmd(client board.BoardProvider, listID string, prev *board.List) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		err := client.ArchiveList(ctx, listID)
-		return listMutatedMsg{action: "archive", listID: listID, err: err}
+		msg := listMutatedMsg{action: "archive", listID: listID, err: err}
+		if err == nil {
+			name := shortID(listID)
+			if prev != nil {
+				name = prev.Name
+			}
+			msg.undo = newUndo("archive list", fmt.Sprintf("archived list %q", name))
+			msg.undo.listID = listID
+		}
+		return msg
+	}
+}
+
+func labelCardCmd(client board.BoardProvider, cardID string, label board.Label, add bool, prev *board.Card) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		action := "label"
+		if !add {
+			action = "unlabel"
+		}
+		err := setLabel(ctx, client, cardID, label.ID, add)
+		msg := cardMutatedMsg{action: action, cardID: cardID, err: err}
+		if err == nil {
+			name := shortID(cardID)
+			if prev != nil {
+				name = prev.Name
+			}
+			text := fmt.Sprintf("added label %s to %q", labelName(label), name)
+			if !add {
+				text = fmt.Sprintf("removed label %s from %q", labelName(label), name)
+			}
+			msg.undo = newUndo(action, text)
+			msg.undo.cardID = cardID
+			msg.undo.labelID = label.ID
+		}
+		return msg
+	}
+}
+
+func assignMemberCmd(client board.BoardProvider, cardID string, member board.Member, add bool, prev *board.Card) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		action := "assign"
+		if !add {
+			action = "unassign"
+		}
+		err := setMember(ctx, client, cardID, member.ID, add)
+		msg := cardMutatedMsg{action: action, cardID: cardID, err: err}
+		if err == nil {
+			name := shortID(cardID)
+			if prev != nil {
+				name = prev.Name
+			}
+			text := fmt.Sprintf("assigned %s to %q", memberName(member), name)
+			if !add {
+				text = fmt.Sprintf("unassigned %s from %q", memberName(member), name)
+			}
+			msg.undo = newUndo(action, text)
+			msg.undo.cardID = cardID
+			msg.undo.memberID = member.ID
+		}
+		return msg
+	}
+}
+
+func setMember(ctx context.Context, client board.BoardProvider, cardID, memberID string, add bool) error {
+	a, ok := client.(board.Assigner)
+	if !ok {
+		return fmt.Errorf("%s members: %w", client.Name(), board.ErrUnsupported)
+	}
+	if add {
+		return a.AddMember(ctx, cardID, memberID)
+	}
+	return a.RemoveMember(ctx, cardID, memberID)
+}
+
+func setLabel(ctx context.Context, client board.BoardProvider, cardID, labelID string, add bool) error {
+	l, ok := client.(board.Labeler)
+	switch {
+	case !ok:
+		return fmt.Errorf("%s labels: %w", client.Name(), board.ErrUnsupported)
+	case add:
+		return l.AddLabel(ctx, cardID, labelID)
+	default:
+		return l.RemoveLabel(ctx, cardID, labelID)
+	}
+}
+
+func setDueCmd(client board.BoardProvider, cardID string, due *time.Time, prev *board.Card) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := setDue(ctx, client, cardID, due)
+		msg := cardMutatedMsg{action: "due", cardID: cardID, err: err}
+		if err == nil && prev != nil {
+			text := fmt.Sprintf("cleared due date of %q", prev.Name)
+			if due != nil {
+				text = fmt.Sprintf("set %q due %s", prev.Name, formatDue(*due))
+			}
+			msg.undo = newUndo("due", text)
+			msg.undo.cardID = cardID
+			msg.undo.due = prev.Due
+		}
+		return msg
+	}
+}
+
+func dueCompleteCmd(client board.BoardProvider, cardID string, complete bool, prev *board.Card) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := setDueComplete(ctx, client, cardID, complete)
+		msg := cardMutatedMsg{action: "due complete", cardID: cardID, err: err}
+		if err == nil {
+			name := shortID(cardID)
+			if prev != nil {
+				name = prev.Name
+			}
+			text := fmt.Sprintf("marked %q done", name)
+			if !complete {
+				text = fmt.Sprintf("marked %q not done", name)
+			}
+			msg.undo = newUndo("due complete", text)
+			msg.undo.cardID = cardID
+			msg.undo.dueComplete = !complete
+		}
+		return msg
+	}
+}
+
+func setDue(ctx context.Context, client board.BoardProvider, cardID string, due *time.Time) error {
+	d, ok := client.(board.DueDater)
+	if !ok {
+		return fmt.Errorf("%s due dates: %w", client.Name(), board.ErrUnsupported)
+	}
+	return d.SetDue(ctx, cardID, due)
+}
+
+func setDueComplete(ctx context.Context, client board.BoardProvider, cardID string, complete bool) error {
+	d, ok := client.(board.DueDater)
+	if !ok {
+		return fmt.Errorf("%s due dates: %w", client.Name(), board.ErrUnsupported)
+	}
+	return d.SetDueComplete(ctx, cardID, complete)
+}
+
+func loadActivityCmd(r board.ActivityReader, cardID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		activity, err := r.Activity(ctx, cardID)
+		return activityLoadedMsg{cardID: cardID, activity: activity, err: err}
+	}
+}
+
+func loadChecklistsCmd(c board.Checklister, cardID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		lists, err := c.Checklists(ctx, cardID)
+		return checklistsLoadedMsg{cardID: cardID, checklists: lists, err: err}
+	}
+}
+
+func checkItemCmd(client board.BoardProvider, cardID string, item board.CheckItem, checked bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := setCheckItem(ctx, client, cardID, item.ID, checked)
+		msg := cardMutatedMsg{action: "check item", cardID: cardID, err: err}
+		if err == nil {
+			text := fmt.Sprintf("checked %q", item.Name)
+			if !checked {
+				text = fmt.Sprintf("unchecked %q", item.Name)
+			}
+			msg.undo = newUndo("check item", text)
+			msg.undo.cardID = cardID
+			msg.undo.item = item
+		}
+		return msg
+	}
+}
+
+// addCheckItemCmd adds an item to the card's checklist named or identified
+// by checklist, the first one when empty. the checklist is created if the
+// card has none by that name.
+func addCheckItemCmd(client board.BoardProvider, cardID, checklist, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		msg := cardMutatedMsg{action: "add item", cardID: cardID}
+		c, err := checklister(client)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		lists, err := c.Checklists(ctx, cardID)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		target := findChecklist(lists, checklist)
+		if target == nil {
+			if checklist == "" {
+				checklist = defaultChecklist
+			}
+			if target, err = c.CreateChecklist(ctx, cardID, checklist); err != nil {
+				msg.err = err
+				return msg
+			}
+		}
+		item, err := c.AddCheckItem(ctx, target.ID, name)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		msg.undo = newUndo("add item", fmt.Sprintf("added %q to %s", name, target.Name))
+		msg.undo.cardID = cardID
+		msg.undo.checklistID = target.ID
+		msg.undo.item = *item
+		return msg
+	}
+}
+
+// convertItemCmd turns a checklist item into a card in the same list as its
+// card, removing the item.
+func convertItemCmd(client board.BoardProvider, card board.Card, checklistID string, item board.CheckItem) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		msg := cardMutatedMsg{action: "convert item", cardID: card.ID}
+		c, err := checklister(client)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		created, err := client.CreateCard(ctx, card.IDList, item.Name)
+		if err != nil {
+			msg.err = err
+			return msg
+		}
+		if err := c.DeleteCheckItem(ctx, card.ID, item.ID); err != nil {
+			msg.err = fmt.Errorf("created card %q but couldn't remove the item: %w", item.Name, err)
+			return msg
+		}
+		msg.undo = newUndo("convert item", fmt.Sprintf("turned %q into a card", item.Name))
+		msg.undo.cardID = card.ID
+		msg.undo.checklistID = checklistID
+		msg.undo.item = item
+		msg.undo.convertedID = created.ID
+		return msg
+	}
+}
+
+func checklister(client board.BoardProvider) (board.Checklister, error) {
+	c, ok := client.(board.Checklister)
+	if !ok {
+		return nil, fmt.Errorf("%s checklists: %w", client.Name(), board.ErrUnsupported)
+	}
+	return c, nil
+}
+
+func setCheckItem(ctx context.Context, client board.BoardProvider, cardID, itemID string, checked bool) error {
+	c, err := checklister(client)
+	if err != nil {
+		return err
+	}
+	return c.SetCheckItem(ctx, cardID, itemID, checked)
+}
+
+// restoreCheckItem puts a removed item back on its checklist.
+func restoreCheckItem(ctx context.Context, client board.BoardProvider, e undoEntry) error {
+	c, err := checklister(client)
+	if err != nil {
+		return err
+	}
+	item, err := c.AddCheckItem(ctx, e.checklistID, e.item.Name)
+	if err != nil || !e.item.Checked {
+		return err
+	}
+	return c.SetCheckItem(ctx, e.cardID, item.ID, true)
+}
+
+// undoCmd applies the inverse of a recorded mutation.
+func undoCmd(client board.BoardProvider, e undoEntry) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		var err error
+		switch e.action {
+		case "move":
+			err = client.MoveCard(ctx, e.cardID, e.listID)
+		case "rename", "update":
+			err = client.UpdateCard(ctx, e.cardID, e.fields)
+		case "archive":
+			err = client.RestoreCard(ctx, e.cardID)
+		case "create":
+			err = client.ArchiveCard(ctx, e.cardID)
+		case "create list":
+			err = client.ArchiveList(ctx, e.listID)
+		case "archive list":
+			err = client.RestoreList(ctx, e.listID)
+		case "label":
+			err = setLabel(ctx, client, e.cardID, e.labelID, false)
+		case "unlabel":
+			err = setLabel(ctx, client, e.cardID, e.labelID, true)
+		case "assign":
+			err = setMember(ctx, client, e.cardID, e.memberID, false)
+		case "unassign":
+			err = setMember(ctx, client, e.cardID, e.memberID, true)
+		case "due":
+			err = setDue(ctx, client, e.cardID, e.due)
+		case "due complete":
+			err = setDueComplete(ctx, client, e.cardID, e.dueComplete)
+		case "check item":
+			err = setCheckItem(ctx, client, e.cardID, e.item.ID, e.item.Checked)
+		case "add item":
+			var c board.Checklister
+			if c, err = checklister(client); err == nil {
+				err = c.DeleteCheckItem(ctx, e.cardID, e.item.ID)
+			}
+		case "convert item":
+			if err = client.ArchiveCard(ctx, e.convertedID); err == nil {
+				err = restoreCheckItem(ctx, client, e)
+			}
+		case "comment":
+			if d, ok := client.(board.CommentDeleter); ok {
+				err = d.DeleteComment(ctx, e.cardID, e.commentID)
+			} else {
+				err = fmt.Errorf("%s comments: %w", client.Name(), board.ErrUnsupported)
+			}
+		default:
+			err = fmt.Errorf("don't know how to undo %q", e.action)
+		}
+		return undoneMsg{entry: e, err: err}
 	}
 }
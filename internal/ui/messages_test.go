@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// syncingProvider loads boards in one call and has labels and members.
+type syncingProvider struct {
+	recordingProvider
+}
+
+func (p *syncingProvider) Load(ctx context.Context, boardID string) (*board.Changes, error) {
+	p.record("Load", boardID)
+	return &board.Changes{
+		Cursor: "a1",
+		Lists:  []board.List{{ID: "l1", Name: "Todo"}},
+		Cards:  []board.Card{{ID: "c1", Name: "Login", IDList: "l1"}},
+	}, nil
+}
+func (p *syncingProvider) Changes(ctx context.Context, boardID, since string) (*board.Changes, error) {
+	p.record("Changes", boardID, since)
+	return &board.Changes{Cursor: since}, nil
+}
+func (p *syncingProvider) Labels(ctx context.Context, boardID string) ([]board.Label, error) {
+	p.record("Labels", boardID)
+	return []board.Label{{ID: "lb1", Name: "bug"}}, nil
+}
+func (p *syncingProvider) AddLabel(ctx context.Context, cardID, labelID string) error { return nil }
+func (p *syncingProvider) RemoveLabel(ctx context.Context, cardID, labelID string) error {
+	return nil
+}
+func (p *syncingProvider) Members(ctx context.Context, boardID string) ([]board.Member, error) {
+	p.record("Members", boardID)
+	return []board.Member{{ID: "m1"}}, nil
+}
+func (p *syncingProvider) Me(ctx context.Context) (*board.Member, error) {
+	p.record("Me")
+	return &board.Member{ID: "m1"}, nil
+}
+func (p *syncingProvider) AddMember(ctx context.Context, cardID, memberID string) error { return nil }
+func (p *syncingProvider) RemoveMember(ctx context.Context, cardID, memberID string) error {
+	return nil
+}
+
+func TestLoadBoardDataUsesOneLoad(t *testing.T) {
+	p := &syncingProvider{}
+	msg := loadBoardDataCmd(p, "b1", "Board", false)().(boardDataLoadedMsg)
+	if msg.err != nil {
+		t.Fatal(msg.err)
+	}
+	if !slices.Equal(p.calls, []string{"Load b1"}) {
+		t.Fatalf("calls = %v", p.calls)
+	}
+	if msg.cursor != "a1" || len(msg.lists) != 1 || len(msg.cards) != 1 || msg.meta {
+		t.Fatalf("msg = %+v", msg)
+	}
+}
+
+func TestLoadBoardDataMeta(t *testing.T) {
+	p := &syncingProvider{}
+	msg := loadBoardDataCmd(p, "b1", "Board", true)().(boardDataLoadedMsg)
+	if msg.err != nil {
+		t.Fatal(msg.err)
+	}
+	want := []string{"Load b1", "Labels b1", "Members b1", "Me "}
+	if !slices.Equal(p.calls, want) {
+		t.Fatalf("calls = %v, want %v", p.calls, want)
+	}
+	if !msg.meta || len(msg.labels) != 1 || len(msg.members) != 1 || msg.me != "m1" {
+		t.Fatalf("msg = %+v", msg)
+	}
+}
+
+func TestLoadBoardDataWithoutSyncer(t *testing.T) {
+	p := &recordingProvider{}
+	msg := loadBoardDataCmd(p, "b1", "Board", true)().(boardDataLoadedMsg)
+	if msg.err != nil || msg.cursor != "" {
+		t.Fatalf("msg = %+v", msg)
+	}
+}
+
+func TestLoadBoardCmdFetchesMetaOnSwitch(t *testing.T) {
+	p := &syncingProvider{}
+	m := Model{provider: p, boardID: "b1", kanban: reviewBoard()}
+
+	m.loadBoardCmd("b1", "Board")()
+	if slices.Contains(p.calls, "Labels b1") {
+		t.Fatalf("refresh fetched labels: %v", p.calls)
+	}
+
+	p.calls = nil
+	m.loadBoardCmd("b2", "Other")()
+	if !slices.Contains(p.calls, "Labels b2") || !slices.Contains(p.calls, "Members b2") {
+		t.Fatalf("switch skipped labels or members: %v", p.calls)
+	}
+}
@@ -2,32 +2,23 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/codywilliamson/aboard/internal/agent"
+	"github.com/codywilliamson/aboard/internal/board"
 	"github.com/codywilliamson/aboard/internal/config"
-	"github.com/codywilliamson/aboard/internal/trello"
 )
 
-type trelloClient interface {
-	CanAuth() bool
-	Boards(context.Context) ([]trello.Board, error)
-	CardsForBoard(context.Context, string) ([]trello.Card, error)
-	ListsForBoard(context.Context, string) ([]trello.List, error)
-	MoveCard(context.Context, string, string) error
-	UpdateCard(context.Context, string, string, string) error
-	AddComment(context.Context, string, string) error
-	ArchiveCard(context.Context, string) error
-	CreateCard(context.Context, string, string) (*trello.Card, error)
-	CreateList(context.Context, string, string) (*trello.List, error)
-	ArchiveList(context.Context, string) error
-}
-
 type agentRunner interface {
-	Ask(context.Context, agent.AgentName, string, string) (string, error)
+	Agents() []agent.AgentName
+	Stream(context.Context, agent.AgentName, string, string) (<-chan agent.Event, error)
 }
 
 type viewMode int
@@ -46,12 +37,13 @@ const (
 )
 
 type Model struct {
-	cfg    config.Config
-	trello trelloClient
-	runner agentRunner
+	cfg      config.Config
+	provider board.BoardProvider
+	runner   agentRunner
 
 	mode       viewMode
 	focus      focusArea
+	agents     []agent.AgentName
 	active     agent.AgentName
 	status     string
 	errText    string
@@ -61,7 +53,7 @@ type Model struct {
 	width  int
 	height int
 
-	boards      []trello.Board
+	boards      []board.Board
 	boardCursor int
 	boardID     string
 	boardName   string
@@ -70,6 +62,9 @@ type Model struct {
 	drawer     DrawerModel
 	prompt     PromptBar
 	help       HelpModel
+	undo       undoStack
+	search     searchOverlay
+	editor     descEditor
 	drawerOpen bool
 
 	// operation targets for prompt actions
@@ -77,20 +72,52 @@ type Model struct {
 	opListID string
 
 	pendingPrompt string
+	cancelAsk     context.CancelFunc
+	escArmed      bool
+	editingAction bool
+
+	// incremental sync position and when the board was last fully loaded
+	syncCursor   string
+	lastFullLoad time.Time
+
+	// a background poll is in flight; pollEdits is kanban.edits when it began
+	// and pollQueued asks for another once it's done
+	polling    bool
+	pollEdits  int
+	pollQueued bool
+
+	// the board the provider pushes changes for, if it can
+	watching    string
+	cancelWatch context.CancelFunc
+
+	// a card to select once its board has loaded, from a search of every board
+	focusCard string
 }
 
-func NewModel(cfg config.Config, tc trelloClient, runner agentRunner) Model {
+// fullReloadEvery bounds how long incremental syncs are trusted before the
+// whole board is fetched again.
+const fullReloadEvery = 5 * time.Minute
+
+func NewModel(cfg config.Config, provider board.BoardProvider, runner agentRunner) Model {
+	agents := runner.Agents()
+	var active agent.AgentName
+	if len(agents) > 0 {
+		active = agents[0]
+	}
 	return Model{
-		cfg:     cfg,
-		trello:  tc,
-		runner:  runner,
-		mode:    modeKanban,
-		focus:   focusKanban,
-		active:  agent.AgentCodex,
-		status:  "loading...",
-		boardID: cfg.TrelloBoardID,
-		drawer:  NewDrawerModel(),
-		prompt:  NewPromptBar(),
+		cfg:      cfg,
+		provider: provider,
+		runner:   runner,
+		mode:     modeKanban,
+		focus:    focusKanban,
+		agents:   agents,
+		active:   active,
+		status:   "loading...",
+		boardID:  cfg.BoardID,
+		drawer:   NewDrawerModel(),
+		prompt:   NewPromptBar(),
+		search:   newSearchOverlay(),
+		editor:   newDescEditor(),
 		kanban: KanbanModel{
 			cardCursors: make(map[string]int),
 		},
@@ -98,13 +125,14 @@ func NewModel(cfg config.Config, tc trelloClient, runner agentRunner) Model {
 }
 
 func (m Model) Init() tea.Cmd {
-	if !m.trello.CanAuth() {
+	if !m.provider.CanAuth() {
 		return nil
 	}
+	poll := pollCmd(m.cfg.PollInterval)
 	if m.boardID != "" {
-		return loadBoardDataCmd(m.trello, m.boardID, "")
+		return tea.Batch(loadBoardDataCmd(m.provider, m.boardID, "", true), poll)
 	}
-	return loadBoardsCmd(m.trello)
+	return tea.Batch(loadBoardsCmd(m.provider), poll)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -133,12 +161,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case boardDataLoadedMsg:
+		if msg.poll {
+			return m.applyPolledData(msg)
+		}
 		m.loading = false
+		focusCard := m.focusCard
+		m.focusCard = ""
 		if msg.err != nil {
 			m.errText = msg.err.Error()
 			m.status = "failed to load board"
 			return m, nil
 		}
+		sameBoard := msg.boardID == m.boardID && m.kanban.lists != nil
 		m.boardID = msg.boardID
 		if msg.boardName != "" {
 			m.boardName = msg.boardName
@@ -147,35 +181,178 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.errText = ""
 		m.mode = modeKanban
-		m.kanban.SetData(msg.lists, msg.cards)
+		if sameBoard {
+			m.kanban.Refresh(msg.lists, msg.cards)
+		} else {
+			m.kanban.SetData(msg.lists, msg.cards)
+		}
+		if msg.meta {
+			m.kanban.labels = msg.labels
+			m.kanban.members, m.kanban.me = msg.members, msg.me
+		}
+		dropped := ""
+		if !sameBoard && m.kanban.filter.Active() {
+			// the filter carries over to another board if its labels and
+			// members are there too
+			f, err := parseFilter(m.kanban.filter.String(), &m.kanban)
+			if err != nil {
+				dropped = "filter cleared: " + err.Error()
+			}
+			m.kanban.filter = f
+		}
+		m.syncDrawerCard(sameBoard)
+		m.syncCursor = msg.cursor
+		m.lastFullLoad = time.Now()
 		m.recalcLayout()
 		if len(msg.lists) == 0 {
 			m.status = "board loaded (no lists)"
 		} else {
 			m.status = "h/l: columns  j/k: cards  enter: open  ?: help"
 		}
+		if dropped != "" {
+			m.status = dropped
+		}
+		if focusCard != "" {
+			if c := m.kanban.findCard(focusCard); c != nil {
+				m.jumpToCard(c.ID, c.Name)
+			}
+		}
+		return m, tea.Batch(m.watchBoard(), m.checklistsCmd(false))
+
+	case boardSyncedMsg:
+		if msg.poll {
+			return m.applyPolledSync(msg)
+		}
+		if msg.boardID != m.boardID {
+			return m, nil
+		}
+		if msg.err != nil || msg.changes.Reload {
+			return m, m.loadBoardCmd(m.boardID, m.boardName)
+		}
+		m.kanban.Patch(msg.changes)
+		m.syncCursor = msg.changes.Cursor
+		m.syncDrawerCard(true)
+		return m, m.checklistsCmd(false)
+
+	case externalEditedMsg:
+		if !m.editor.visible || m.editor.card.ID != msg.cardID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.status = "editor failed: " + msg.err.Error()
+			return m, nil
+		}
+		m.editor.SetValue(msg.text)
+		m.status = "edited in $EDITOR — ctrl+s: save  esc: cancel"
+		return m, nil
+
+	case searchDueMsg:
+		s, ok := m.provider.(board.Searcher)
+		query := strings.TrimSpace(m.search.input.Value())
+		if !ok || !m.search.visible || !m.search.global || msg.seq != m.search.seq || query == "" {
+			return m, nil
+		}
+		return m, searchBoardsCmd(s, query, msg.seq)
+
+	case searchResultsMsg:
+		if msg.seq != m.search.seq || !m.search.global {
+			return m, nil
+		}
+		m.search.SetFound(msg.found, msg.err)
 		return m, nil
 
+	case activityLoadedMsg:
+		if m.drawer.card == nil || m.drawer.card.ID != msg.cardID {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.errText = msg.err.Error()
+			return m, nil
+		}
+		m.drawer.SetActivity(msg.activity)
+		return m, nil
+
+	case checklistsLoadedMsg:
+		if msg.err != nil {
+			m.errText = msg.err.Error()
+			return m, nil
+		}
+		if m.kanban.checklists == nil {
+			m.kanban.checklists = make(map[string][]board.Checklist)
+		}
+		m.kanban.checklists[msg.cardID] = msg.checklists
+		m.syncDrawerCard(true)
+		return m, nil
+
+	case watchStartedMsg:
+		if msg.boardID != m.watching {
+			return m, nil
+		}
+		if msg.err != nil {
+			// providers that can't watch without more config stay quiet
+			if !errors.Is(msg.err, board.ErrUnsupported) {
+				m.errText = msg.err.Error()
+				m.drawer.AppendTimeline("system", "live updates unavailable: "+msg.err.Error())
+			}
+			return m, nil
+		}
+		m.drawer.AppendTimeline("system", "live updates on")
+		return m, waitBoardEventCmd(msg.events)
+
+	case boardEventMsg:
+		var cmd tea.Cmd
+		if msg.event.BoardID == m.boardID {
+			cmd = m.startPoll()
+		}
+		return m, tea.Batch(cmd, waitBoardEventCmd(msg.events))
+
+	case pollTickMsg:
+		cmd := m.startPoll()
+		return m, tea.Batch(cmd, pollCmd(m.cfg.PollInterval))
+
+	case agentChunkMsg:
+		m.drawer.AppendStream(msg.text)
+		m.status = fmt.Sprintf("%s is responding...", msg.agent)
+		return m, waitAgentCmd(msg.agent, msg.prompt, msg.events)
+
+	case spinner.TickMsg:
+		if !m.runningAsk {
+			return m, nil
+		}
+		return m, m.drawer.TickSpinner(msg)
+
 	case agentResponseMsg:
 		m.runningAsk = false
+		m.escArmed = false
+		if m.cancelAsk != nil {
+			m.cancelAsk()
+			m.cancelAsk = nil
+		}
+		if errors.Is(msg.err, context.Canceled) {
+			m.errText = ""
+			m.status = "agent cancelled"
+			m.restorePendingPrompt()
+			m.drawer.EndStream(msg.output)
+			m.drawer.AppendTimeline(string(msg.agent), "cancelled")
+			return m, nil
+		}
 		if msg.err != nil {
 			m.errText = msg.err.Error()
 			m.status = "agent failed"
-			if strings.TrimSpace(m.pendingPrompt) != "" && strings.TrimSpace(m.prompt.Value()) == "" {
-				m.prompt.input.SetValue(m.pendingPrompt)
-			}
+			m.restorePendingPrompt()
+			// keep whatever streamed before the failure
+			m.drawer.EndStream(msg.output)
 			m.drawer.AppendTimeline(string(msg.agent)+" error", msg.err.Error())
-			m.pendingPrompt = ""
 			return m, nil
 		}
 		m.errText = ""
 		m.status = fmt.Sprintf("%s responded", msg.agent)
+		// actions are only parsed once the full output is in
 		displayText, actions := parseActions(msg.output)
-		m.drawer.AppendTimeline(string(msg.agent), displayText)
+		m.drawer.EndStream(displayText)
 		m.pendingPrompt = ""
 		if len(actions) > 0 {
-			m.status = fmt.Sprintf("executing %d action(s)...", len(actions))
-			return m, executeActions(m.trello, m.boardID, actions)
+			return m.dispatchActions(actions)
 		}
 		return m, nil
 
@@ -183,25 +360,51 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err != nil {
 			m.errText = msg.err.Error()
 			m.status = fmt.Sprintf("%s failed", msg.action)
-			m.drawer.AppendTimeline("system", fmt.Sprintf("%s failed: %s", msg.action, msg.err.Error()))
-			return m, nil
+			m.drawer.AppendTimeline("system", fmt.Sprintf("%s failed: %s%s", msg.action, msg.err.Error(), m.rollback(msg.pending)))
+			return m, m.checklistsChanged(msg.action, msg.cardID)
 		}
 		m.errText = ""
 		m.status = fmt.Sprintf("card %s ok", msg.action)
 		m.drawer.AppendTimeline("system", fmt.Sprintf("card %s ok", msg.action))
-		return m, loadBoardDataCmd(m.trello, m.boardID, m.boardName)
+		m.settle(msg.pending, msg.cardID)
+		if msg.undo != nil {
+			m.undo.Push(*msg.undo)
+		}
+		return m, tea.Batch(m.reloadCmd(), m.checklistsChanged(msg.action, msg.cardID), m.activityCmd(msg.cardID))
 
 	case listMutatedMsg:
 		if msg.err != nil {
 			m.errText = msg.err.Error()
 			m.status = fmt.Sprintf("list %s failed", msg.action)
-			m.drawer.AppendTimeline("system", fmt.Sprintf("list %s failed: %s", msg.action, msg.err.Error()))
+			m.drawer.AppendTimeline("system", fmt.Sprintf("list %s failed: %s%s", msg.action, msg.err.Error(), m.rollback(msg.pending)))
 			return m, nil
 		}
 		m.errText = ""
 		m.status = fmt.Sprintf("list %s ok", msg.action)
 		m.drawer.AppendTimeline("system", fmt.Sprintf("list %s ok", msg.action))
-		return m, loadBoardDataCmd(m.trello, m.boardID, m.boardName)
+		m.settle(msg.pending, msg.listID)
+		if msg.undo != nil {
+			m.undo.Push(*msg.undo)
+		}
+		return m, m.reloadCmd()
+
+	case undoRequestMsg:
+		cmd := m.undoLast()
+		return m, cmd
+
+	case undoneMsg:
+		if msg.err != nil {
+			// keep it on the stack so the undo can be retried
+			m.undo.Push(msg.entry)
+			m.errText = msg.err.Error()
+			m.status = "undo failed"
+			m.drawer.AppendTimeline("system", fmt.Sprintf("undo failed: %s", msg.err.Error()))
+			return m, nil
+		}
+		m.errText = ""
+		m.status = "undid: " + msg.entry.label
+		m.drawer.AppendTimeline("system", "undid: "+msg.entry.label)
+		return m, tea.Batch(m.reloadCmd(), m.checklistsChanged(msg.entry.action, msg.entry.cardID), m.activityCmd(msg.entry.cardID))
 
 	case tea.KeyMsg:
 		return m.handleKey(msg)
@@ -217,6 +420,10 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 	}
 
+	if m.editor.visible {
+		return m.updateEditorKeys(msg)
+	}
+
 	if m.help.visible {
 		if key == "?" || key == "esc" {
 			m.help.visible = false
@@ -224,13 +431,28 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.undo.visible {
+		switch key {
+		case "U", "esc":
+			m.undo.visible = false
+		case "u":
+			cmd := m.undoLast()
+			return m, cmd
+		}
+		return m, nil
+	}
+
+	if m.search.visible {
+		return m.updateSearchKeys(msg)
+	}
+
 	// global keys
 	switch key {
 	case "?":
 		m.help.visible = true
 		return m, nil
 	case "ctrl+a":
-		m.toggleAgent()
+		m.cycleAgent()
 		return m, nil
 	case "ctrl+r":
 		cmd := m.refreshData()
@@ -238,6 +460,19 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "ctrl+b":
 		cmd := m.openBoardSelector()
 		return m, cmd
+	case "ctrl+x":
+		m.cancelAgent()
+		return m, nil
+	case "ctrl+f":
+		_, canGlobal := m.provider.(board.Searcher)
+		if m.mode == modeKanban {
+			cmd := m.search.Open(false, canGlobal)
+			return m, cmd
+		}
+		if canGlobal {
+			cmd := m.search.Open(true, true)
+			return m, cmd
+		}
 	}
 
 	if m.mode == modeBoardSelect {
@@ -255,6 +490,66 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+func (m Model) updateSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+f":
+		m.search.Close()
+		return m, nil
+	case "up", "ctrl+p":
+		m.search.Move(-1)
+		return m, nil
+	case "down", "ctrl+n":
+		m.search.Move(1)
+		return m, nil
+	case "tab":
+		cmd := m.search.ToggleScope(&m.kanban)
+		return m, cmd
+	case "enter":
+		hit := m.search.Selected()
+		if hit == nil {
+			return m, nil
+		}
+		m.search.Close()
+		cmd := m.openSearchHit(*hit)
+		return m, cmd
+	}
+	cmd := m.search.Update(msg, &m.kanban)
+	return m, cmd
+}
+
+// openSearchHit selects a search result's card, loading its board first when
+// it is on another one. a board result just opens the board.
+func (m *Model) openSearchHit(hit searchHit) tea.Cmd {
+	if hit.board != nil && (hit.board.ID != m.boardID || m.kanban.lists == nil) {
+		m.focusCard = hit.card.ID
+		m.focus = focusKanban
+		m.loading = true
+		m.errText = ""
+		m.status = fmt.Sprintf("loading %q...", hit.board.Name)
+		return m.loadBoardCmd(hit.board.ID, hit.board.Name)
+	}
+	m.mode = modeKanban
+	if hit.card.ID == "" {
+		return nil
+	}
+	m.focus = focusKanban
+	m.jumpToCard(hit.card.ID, hit.card.Name)
+	return nil
+}
+
+func (m *Model) jumpToCard(cardID, name string) {
+	filtered := m.kanban.filter.Active()
+	if !m.kanban.jumpTo(cardID) {
+		m.status = fmt.Sprintf("%q is no longer on the board", name)
+		return
+	}
+	m.status = "jumped to " + ellipsis(name, 40)
+	if filtered && !m.kanban.filter.Active() {
+		m.status += " — filter cleared"
+	}
+	m.kanban.markSeen()
+}
+
 func (m Model) updateKanbanKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 	switch key {
@@ -270,15 +565,20 @@ func (m Model) updateKanbanKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if card := m.kanban.SelectedCard(); card != nil {
 			m.kanban.contextCard = card
 			m.drawer.SetCard(card)
+			m.drawer.SetMembers(m.kanban.memberList(card.MemberIDs))
 			m.drawerOpen = true
 			m.focus = focusDrawer
 			m.recalcLayout()
 			m.status = "drawer open — tab: switch focus  esc: close"
+			m.kanban.markSeen()
+			return m, tea.Batch(m.checklistsCmd(false), m.activityCmd(card.ID))
 		}
 	case "m":
 		return m.startMoveCard()
 	case "e":
 		return m.startRenameCard()
+	case "E":
+		return m.startEditDesc()
 	case "c":
 		return m.startCommentCard()
 	case "n":
@@ -287,18 +587,37 @@ func (m Model) updateKanbanKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.startNewList()
 	case "x":
 		return m.startArchiveCard()
+	case "L":
+		return m.startLabelCard()
+	case "M":
+		return m.startMemberCard()
+	case "d":
+		return m.startDueCard()
+	case "D":
+		cmd := m.toggleDueComplete()
+		return m, cmd
+	case " ":
+		cmd := m.toggleMe()
+		return m, cmd
+	case "F":
+		m.toggleMyCards()
+	case "f":
+		return m.startFilter()
 	case "X":
 		return m.startArchiveList()
+	case "u":
+		cmd := m.undoLast()
+		return m, cmd
+	case "U":
+		m.undo.visible = true
 	case "/", "tab":
 		m.focusPromptBar(promptAgent)
 	case "q":
 		return m, tea.Quit
-	case "1":
-		m.setAgent(agent.AgentCodex)
-	case "2":
-		m.setAgent(agent.AgentClaude)
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		m.pickAgent(int(key[0] - '1'))
 	case "a":
-		m.toggleAgent()
+		m.cycleAgent()
 	case "r":
 		cmd := m.refreshData()
 		return m, cmd
@@ -306,11 +625,31 @@ func (m Model) updateKanbanKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		cmd := m.openBoardSelector()
 		return m, cmd
 	}
+	m.kanban.markSeen()
 	return m, nil
 }
 
 func (m Model) updateDrawerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
+
+	// while an agent runs, esc esc cancels it instead of closing the drawer
+	if key == "esc" && m.runningAsk {
+		if m.escArmed {
+			m.cancelAgent()
+		} else {
+			m.escArmed = true
+			m.status = "esc again to cancel the agent"
+		}
+		return m, nil
+	}
+	m.escArmed = false
+
+	if m.drawer.review.Active() {
+		if next, cmd, ok := m.updateReviewKeys(key); ok {
+			return next, cmd
+		}
+	}
+
 	switch key {
 	case "esc":
 		m.drawerOpen = false
@@ -334,14 +673,77 @@ func (m Model) updateDrawerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.startMoveCard()
 	case "e":
 		return m.startRenameCard()
+	case "E":
+		return m.startEditDesc()
 	case "c":
 		return m.startCommentCard()
 	case "x":
 		return m.startArchiveCard()
+	case "L":
+		return m.startLabelCard()
+	case "M":
+		return m.startMemberCard()
+	case "d":
+		return m.startDueCard()
+	case "D":
+		cmd := m.toggleDueComplete()
+		return m, cmd
+	case "J":
+		m.drawer.MoveCheck(1)
+		return m, nil
+	case "K":
+		m.drawer.MoveCheck(-1)
+		return m, nil
+	case " ":
+		cmd := m.toggleCheckItem()
+		return m, cmd
+	case "i":
+		return m.startAddCheckItem()
+	case "T":
+		cmd := m.convertCheckItem()
+		return m, cmd
+	case "u":
+		cmd := m.undoLast()
+		return m, cmd
+	case "U":
+		m.undo.visible = true
+		return m, nil
 	}
 	return m, nil
 }
 
+// updateReviewKeys handles the approval checklist. ok is false for keys the
+// drawer should handle as usual.
+func (m Model) updateReviewKeys(key string) (next tea.Model, cmd tea.Cmd, ok bool) {
+	switch key {
+	case "j", "down":
+		m.drawer.review.Move(1)
+	case "k", "up":
+		m.drawer.review.Move(-1)
+	case "y", " ":
+		m.drawer.review.Mark(reviewApproved)
+	case "n":
+		m.drawer.review.Mark(reviewRejected)
+	case "Y":
+		m.drawer.review.MarkAll(reviewApproved)
+	case "N":
+		m.drawer.review.MarkAll(reviewRejected)
+	case "e":
+		next, cmd = m.startEditAction()
+		return next, cmd, true
+	case "enter":
+		next, cmd = m.runReviewed()
+		return next, cmd, true
+	case "esc":
+		m.drawer.review.MarkAll(reviewRejected)
+		next, cmd = m.runReviewed()
+		return next, cmd, true
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
 func (m Model) updatePromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	key := msg.String()
 
@@ -365,6 +767,24 @@ func (m Model) updatePromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case promptLabels, promptMembers:
+		switch key {
+		case "h", "left":
+			m.prompt.MoveOption(-1)
+		case "l", "right":
+			m.prompt.MoveOption(1)
+		case " ":
+			cmd := m.toggleOption()
+			return m, cmd
+		case "enter":
+			cmd := m.toggleOption()
+			m.cancelPrompt()
+			return m, cmd
+		case "esc":
+			m.cancelPrompt()
+		}
+		return m, nil
+
 	case promptConfirmArchiveCard:
 		switch key {
 		case "y":
@@ -407,9 +827,8 @@ func (m *Model) focusPromptBar(mode promptMode) {
 }
 
 func (m Model) startMoveCard() (tea.Model, tea.Cmd) {
-	card := m.kanban.SelectedCard()
+	card := m.selectedCard()
 	if card == nil {
-		m.status = "no card selected"
 		return m, nil
 	}
 	m.opCardID = card.ID
@@ -421,9 +840,8 @@ func (m Model) startMoveCard() (tea.Model, tea.Cmd) {
 }
 
 func (m Model) startRenameCard() (tea.Model, tea.Cmd) {
-	card := m.kanban.SelectedCard()
+	card := m.selectedCard()
 	if card == nil {
-		m.status = "no card selected"
 		return m, nil
 	}
 	m.opCardID = card.ID
@@ -433,10 +851,64 @@ func (m Model) startRenameCard() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) startEditDesc() (tea.Model, tea.Cmd) {
+	card := m.selectedCard()
+	if card == nil {
+		return m, nil
+	}
+	cmd := m.editor.Open(*card)
+	m.recalcLayout()
+	m.status = "editing description"
+	return m, cmd
+}
+
+func (m Model) updateEditorKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+s":
+		return m.saveDesc()
+	case "ctrl+p":
+		m.editor.TogglePreview()
+		return m, nil
+	case "ctrl+o":
+		return m, editExternallyCmd(m.editor.card.ID, m.editor.Value())
+	case "esc":
+		if m.editor.Modified() && !m.editor.discardArmed {
+			m.editor.discardArmed = true
+			m.status = "unsaved changes — esc again to discard, ctrl+s to save"
+			return m, nil
+		}
+		m.editor.Close()
+		m.status = "description unchanged"
+		return m, nil
+	}
+	cmd := m.editor.Update(msg)
+	return m, cmd
+}
+
+// saveDesc writes the editor's text to the card. the editor stays open if
+// the card has gone, so nothing typed is lost.
+func (m Model) saveDesc() (tea.Model, tea.Cmd) {
+	card := m.editor.card
+	if !m.editor.Modified() {
+		m.editor.Close()
+		m.status = "description unchanged"
+		return m, nil
+	}
+	if m.kanban.findCard(card.ID) == nil {
+		m.status = fmt.Sprintf("%q is no longer on the board", card.Name)
+		return m, nil
+	}
+	value := m.editor.Value()
+	m.editor.Close()
+	m.status = "saving description..."
+	cmd := updateCard(m.provider, &m.kanban, card.ID, board.CardUpdate{Desc: &value})
+	m.syncDrawerCard(true)
+	return m, cmd
+}
+
 func (m Model) startCommentCard() (tea.Model, tea.Cmd) {
-	card := m.kanban.SelectedCard()
+	card := m.selectedCard()
 	if card == nil {
-		m.status = "no card selected"
 		return m, nil
 	}
 	m.opCardID = card.ID
@@ -446,9 +918,8 @@ func (m Model) startCommentCard() (tea.Model, tea.Cmd) {
 }
 
 func (m Model) startNewCard() (tea.Model, tea.Cmd) {
-	list := m.kanban.ActiveList()
+	list := m.activeList()
 	if list == nil {
-		m.status = "no list selected"
 		return m, nil
 	}
 	m.opListID = list.ID
@@ -464,9 +935,8 @@ func (m Model) startNewList() (tea.Model, tea.Cmd) {
 }
 
 func (m Model) startArchiveCard() (tea.Model, tea.Cmd) {
-	card := m.kanban.SelectedCard()
+	card := m.selectedCard()
 	if card == nil {
-		m.status = "no card selected"
 		return m, nil
 	}
 	m.opCardID = card.ID
@@ -477,10 +947,241 @@ func (m Model) startArchiveCard() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) startLabelCard() (tea.Model, tea.Cmd) {
+	card := m.selectedCard()
+	if card == nil {
+		return m, nil
+	}
+	if _, ok := m.provider.(board.Labeler); !ok {
+		m.status = m.provider.Name() + " doesn't support labels"
+		return m, nil
+	}
+	if len(m.kanban.labels) == 0 {
+		m.status = "board has no labels"
+		return m, nil
+	}
+	m.opCardID = card.ID
+	m.prompt.SetLabels(m.kanban.labels, card.Labels)
+	m.focus = focusPrompt
+	m.prompt.Focus()
+	m.status = "h/l: pick label  space: toggle  enter: toggle + close  esc: done"
+	return m, nil
+}
+
+func (m Model) startMemberCard() (tea.Model, tea.Cmd) {
+	card := m.selectedCard()
+	if card == nil {
+		return m, nil
+	}
+	if _, ok := m.provider.(board.Assigner); !ok {
+		m.status = m.provider.Name() + " doesn't support members"
+		return m, nil
+	}
+	if len(m.kanban.members) == 0 {
+		m.status = "board has no members"
+		return m, nil
+	}
+	m.opCardID = card.ID
+	m.prompt.SetMembers(m.kanban.members, card.MemberIDs, m.kanban.me)
+	m.focus = focusPrompt
+	m.prompt.Focus()
+	m.status = "h/l: pick member  space: toggle  enter: toggle + close  esc: done"
+	return m, nil
+}
+
+// toggleMe joins or leaves the selected card.
+func (m *Model) toggleMe() tea.Cmd {
+	card := m.selectedCard()
+	if card == nil {
+		return nil
+	}
+	if _, ok := m.provider.(board.Assigner); !ok {
+		m.status = m.provider.Name() + " doesn't support members"
+		return nil
+	}
+	if m.kanban.me == "" {
+		m.status = "don't know who you are on " + m.provider.Name()
+		return nil
+	}
+	join := !slices.Contains(card.MemberIDs, m.kanban.me)
+	if join {
+		m.status = "joining card..."
+	} else {
+		m.status = "leaving card..."
+	}
+	cmd := assignMember(m.provider, &m.kanban, card.ID, m.kanban.member(m.kanban.me), join)
+	m.syncDrawerCard(true)
+	return cmd
+}
+
+// toggleMyCards adds @me to the board filter, or takes it out again.
+func (m *Model) toggleMyCards() {
+	if m.kanban.me == "" {
+		m.status = "don't know who you are on " + m.provider.Name()
+		return
+	}
+	f := m.kanban.filter
+	if i := f.memberTerm(&m.kanban, m.kanban.me); i >= 0 {
+		f.terms = slices.Delete(slices.Clone(f.terms), i, i+1)
+	} else {
+		var err error
+		if f, err = parseFilter(strings.TrimSpace(f.String()+" @me"), &m.kanban); err != nil {
+			m.status = err.Error()
+			return
+		}
+	}
+	m.setFilter(f)
+}
+
+func (m Model) startFilter() (tea.Model, tea.Cmd) {
+	if m.kanban.lists == nil {
+		return m, nil
+	}
+	m.focusPromptBar(promptFilter)
+	m.prompt.Prefill(m.kanban.filter.String())
+	m.status = "enter: filter the board (empty shows all)  esc: cancel"
+	return m, nil
+}
+
+func (m Model) submitFilter(value string) (tea.Model, tea.Cmd) {
+	f, err := parseFilter(value, &m.kanban)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	m.cancelPrompt()
+	m.setFilter(f)
+	return m, nil
+}
+
+func (m *Model) setFilter(f cardFilter) {
+	m.kanban.filter = f
+	if !f.Active() {
+		m.status = "showing all cards"
+		return
+	}
+	m.status = fmt.Sprintf("showing %d of %d cards — f: edit filter", len(m.kanban.filteredCards()), m.kanban.cardCount())
+}
+
+func (m Model) startDueCard() (tea.Model, tea.Cmd) {
+	card := m.selectedCard()
+	if card == nil {
+		return m, nil
+	}
+	if _, ok := m.provider.(board.DueDater); !ok {
+		m.status = m.provider.Name() + " doesn't support due dates"
+		return m, nil
+	}
+	m.opCardID = card.ID
+	m.focusPromptBar(promptDue)
+	if card.Due != nil {
+		m.prompt.Prefill(dueInput(*card.Due))
+	}
+	m.status = "enter: set due date (empty or none clears)  esc: cancel"
+	return m, nil
+}
+
+// toggleDueComplete marks the selected card's due date done, or not done.
+func (m *Model) toggleDueComplete() tea.Cmd {
+	card := m.selectedCard()
+	if card == nil {
+		return nil
+	}
+	if _, ok := m.provider.(board.DueDater); !ok {
+		m.status = m.provider.Name() + " doesn't support due dates"
+		return nil
+	}
+	if card.Due == nil {
+		m.status = "card has no due date — d: set one"
+		return nil
+	}
+	if card.DueComplete {
+		m.status = "marking not done..."
+	} else {
+		m.status = "marking done..."
+	}
+	cmd := setCardDueComplete(m.provider, &m.kanban, card.ID, !card.DueComplete)
+	m.syncDrawerCard(true)
+	return cmd
+}
+
+func (m Model) startAddCheckItem() (tea.Model, tea.Cmd) {
+	card := m.drawer.card
+	if card == nil || isPending(card.ID) {
+		return m, nil
+	}
+	if _, ok := m.provider.(board.Checklister); !ok {
+		m.status = m.provider.Name() + " doesn't support checklists"
+		return m, nil
+	}
+	m.opCardID = card.ID
+	m.focusPromptBar(promptCheckItem)
+	m.status = "enter: add checklist item  esc: cancel"
+	return m, nil
+}
+
+// toggleCheckItem checks or unchecks the drawer's current checklist item.
+func (m *Model) toggleCheckItem() tea.Cmd {
+	card := m.drawer.card
+	_, item := m.drawer.CurrentCheckItem()
+	if card == nil || item == nil {
+		m.status = "no checklist item selected"
+		return nil
+	}
+	m.status = "updating checklist..."
+	cmd := checkItem(m.provider, &m.kanban, card.ID, *item, !item.Checked)
+	m.syncDrawerCard(true)
+	return cmd
+}
+
+// convertCheckItem turns the drawer's current checklist item into a card.
+func (m *Model) convertCheckItem() tea.Cmd {
+	card := m.drawer.card
+	checklistID, item := m.drawer.CurrentCheckItem()
+	if card == nil || item == nil {
+		m.status = "no checklist item selected"
+		return nil
+	}
+	m.status = "converting item to card..."
+	return convertItemCmd(m.provider, *card, checklistID, *item)
+}
+
+// startEditAction opens the action under the review cursor for editing:
+// moves get the list picker, text actions get the prompt bar.
+func (m Model) startEditAction() (tea.Model, tea.Cmd) {
+	item := m.drawer.review.Current()
+	if item == nil {
+		return m, nil
+	}
+	if item.action.Type == "move_card" {
+		idx := 0
+		for i, l := range m.kanban.lists {
+			if l.ID == item.action.ListID {
+				idx = i
+			}
+		}
+		m.editingAction = true
+		m.prompt.SetMoveLists(m.kanban.lists, idx)
+		m.focus = focusPrompt
+		m.prompt.Focus()
+		m.status = "h/l: pick list  enter: confirm  esc: cancel"
+		return m, nil
+	}
+	text := item.action.editText()
+	if text == nil {
+		m.status = "nothing to edit on " + item.action.Type
+		return m, nil
+	}
+	m.editingAction = true
+	m.focusPromptBar(promptEditAction)
+	m.prompt.Prefill(*text)
+	m.status = "enter: save edit  esc: cancel"
+	return m, nil
+}
+
 func (m Model) startArchiveList() (tea.Model, tea.Cmd) {
-	list := m.kanban.ActiveList()
+	list := m.activeList()
 	if list == nil {
-		m.status = "no list selected"
 		return m, nil
 	}
 	m.opListID = list.ID
@@ -491,34 +1192,89 @@ func (m Model) startArchiveList() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// selectedCard is the card prompt actions apply to. cards still being
+// created can't be acted on until the provider has assigned an id.
+func (m *Model) selectedCard() *board.Card {
+	card := m.kanban.SelectedCard()
+	switch {
+	case card == nil:
+		m.status = "no card selected"
+	case isPending(card.ID):
+		m.status = "card is still being created"
+	default:
+		return card
+	}
+	return nil
+}
+
+func (m *Model) activeList() *board.List {
+	list := m.kanban.ActiveList()
+	switch {
+	case list == nil:
+		m.status = "no list selected"
+	case isPending(list.ID):
+		m.status = "list is still being created"
+	default:
+		return list
+	}
+	return nil
+}
+
 // --- prompt submissions ---
 
 func (m Model) submitPrompt() (tea.Model, tea.Cmd) {
 	value := strings.TrimSpace(m.prompt.Value())
+	if m.prompt.mode == promptDue {
+		return m.submitDue(value)
+	}
+	if m.prompt.mode == promptFilter {
+		return m.submitFilter(value)
+	}
 	if value == "" {
 		m.status = "input is empty"
 		return m, nil
 	}
 
+	// cancelPrompt clears the operation targets
+	cardID, listID := m.opCardID, m.opListID
 	switch m.prompt.mode {
 	case promptAgent:
+		if name, ok := strings.CutPrefix(value, "/agent"); ok && (name == "" || name[0] == ' ') {
+			m.selectAgentByName(strings.TrimSpace(name))
+			m.prompt.input.SetValue("")
+			return m, nil
+		}
 		return m.sendAgentPrompt(value)
 	case promptRename:
 		m.cancelPrompt()
 		m.status = "renaming card..."
-		return m, updateCardCmd(m.trello, m.opCardID, value, "")
+		cmd := updateCard(m.provider, &m.kanban, cardID, board.CardUpdate{Name: &value})
+		m.syncDrawerCard(true)
+		return m, cmd
 	case promptComment:
 		m.cancelPrompt()
 		m.status = "adding comment..."
-		return m, addCommentCmd(m.trello, m.opCardID, value)
+		return m, addCommentCmd(m.provider, cardID, value, m.kanban.findCard(cardID))
 	case promptNewCard:
 		m.cancelPrompt()
 		m.status = "creating card..."
-		return m, createCardCmd(m.trello, m.opListID, value)
+		cmd := createCard(m.provider, &m.kanban, listID, value)
+		return m, cmd
 	case promptNewList:
 		m.cancelPrompt()
 		m.status = "creating list..."
-		return m, createListCmd(m.trello, m.boardID, value)
+		cmd := createList(m.provider, &m.kanban, m.boardID, value)
+		return m, cmd
+	case promptEditAction:
+		m.cancelPrompt()
+		m.editAction(func(a *agentAction) { *a.editText() = value })
+		return m, nil
+	case promptCheckItem:
+		// add to the checklist the cursor is on, so items land where expected
+		checklistID, _ := m.drawer.CurrentCheckItem()
+		m.cancelPrompt()
+		m.status = "adding checklist item..."
+		return m, addCheckItemCmd(m.provider, cardID, checklistID, value)
 	}
 	m.cancelPrompt()
 	return m, nil
@@ -527,13 +1283,74 @@ func (m Model) submitPrompt() (tea.Model, tea.Cmd) {
 func (m Model) submitMove() (tea.Model, tea.Cmd) {
 	listID := m.prompt.SelectedListID()
 	cardID := m.opCardID
+	editing := m.editingAction
 	m.cancelPrompt()
+	if editing {
+		if listID != "" {
+			m.editAction(func(a *agentAction) { a.ListID = listID })
+		}
+		return m, nil
+	}
 	if listID == "" || cardID == "" {
 		m.status = "move cancelled"
 		return m, nil
 	}
 	m.status = "moving card..."
-	return m, moveCardCmd(m.trello, cardID, listID)
+	cmd := moveCard(m.provider, &m.kanban, cardID, listID)
+	m.syncDrawerCard(true)
+	return m, cmd
+}
+
+// submitDue sets or clears the due date; unreadable input keeps the prompt
+// open so it can be fixed.
+func (m Model) submitDue(value string) (tea.Model, tea.Cmd) {
+	due, err := parseDue(value, time.Now())
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	cardID := m.opCardID
+	m.cancelPrompt()
+	if due == nil {
+		m.status = "clearing due date..."
+	} else {
+		m.status = "setting due date..."
+	}
+	cmd := setCardDue(m.provider, &m.kanban, cardID, due)
+	m.syncDrawerCard(true)
+	return m, cmd
+}
+
+// toggleOption adds or removes the picker's current label or member on the
+// card right away; the picker stays open for more.
+func (m *Model) toggleOption() tea.Cmd {
+	id, on, ok := m.prompt.ToggleOption()
+	if !ok || m.opCardID == "" {
+		return nil
+	}
+	var cmd tea.Cmd
+	switch m.prompt.mode {
+	case promptLabels:
+		i := labelIndex(m.kanban.labels, id)
+		if i < 0 {
+			return nil
+		}
+		if on {
+			m.status = "adding label..."
+		} else {
+			m.status = "removing label..."
+		}
+		cmd = labelCard(m.provider, &m.kanban, m.opCardID, m.kanban.labels[i], on)
+	case promptMembers:
+		if on {
+			m.status = "assigning member..."
+		} else {
+			m.status = "unassigning member..."
+		}
+		cmd = assignMember(m.provider, &m.kanban, m.opCardID, m.kanban.member(id), on)
+	}
+	m.syncDrawerCard(true)
+	return cmd
 }
 
 func (m Model) submitArchiveCard() (tea.Model, tea.Cmd) {
@@ -543,7 +1360,9 @@ func (m Model) submitArchiveCard() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 	m.status = "archiving card..."
-	return m, archiveCardCmd(m.trello, cardID)
+	cmd := archiveCard(m.provider, &m.kanban, cardID)
+	m.syncDrawerCard(true)
+	return m, cmd
 }
 
 func (m Model) submitArchiveList() (tea.Model, tea.Cmd) {
@@ -553,7 +1372,9 @@ func (m Model) submitArchiveList() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 	m.status = "archiving list..."
-	return m, archiveListCmd(m.trello, listID)
+	cmd := archiveList(m.provider, &m.kanban, listID)
+	m.syncDrawerCard(true)
+	return m, cmd
 }
 
 func (m *Model) cancelPrompt() {
@@ -561,6 +1382,7 @@ func (m *Model) cancelPrompt() {
 	m.prompt.Reset()
 	m.opCardID = ""
 	m.opListID = ""
+	m.editingAction = false
 	if m.drawerOpen {
 		m.focus = focusDrawer
 	} else {
@@ -569,6 +1391,68 @@ func (m *Model) cancelPrompt() {
 	m.status = "h/l: columns  j/k: cards  enter: open  ?: help"
 }
 
+// dispatchActions runs the actions the approval policy lets through and
+// queues the rest in the drawer for review.
+func (m Model) dispatchActions(actions []agentAction) (tea.Model, tea.Cmd) {
+	var run, hold []agentAction
+	for _, a := range actions {
+		if needsApproval(m.cfg.ActionApproval, a) {
+			hold = append(hold, a)
+		} else {
+			run = append(run, a)
+		}
+	}
+
+	if len(hold) > 0 {
+		m.drawer.QueueReview(hold, &m.kanban)
+		m.drawer.AppendTimeline("system", fmt.Sprintf("%d action(s) waiting for approval", len(hold)))
+		if !m.drawerOpen {
+			m.drawerOpen = true
+			m.recalcLayout()
+		}
+		// don't yank the user out of something they're typing
+		if m.focus != focusPrompt {
+			m.focus = focusDrawer
+		}
+		m.status = "review actions — y/n: approve/reject  enter: run approved"
+	}
+	if len(run) == 0 {
+		return m, nil
+	}
+	if len(hold) == 0 {
+		m.status = fmt.Sprintf("executing %d action(s)...", len(run))
+	}
+	cmd := executeActions(m.provider, m.boardID, &m.kanban, run)
+	m.syncDrawerCard(true)
+	return m, cmd
+}
+
+// runReviewed closes the checklist and executes what was approved.
+func (m Model) runReviewed() (tea.Model, tea.Cmd) {
+	approved, dropped := m.drawer.TakeReview()
+	m.drawer.AppendTimeline("system", fmt.Sprintf("approved %d action(s), rejected %d", len(approved), dropped))
+	if len(approved) == 0 {
+		m.status = "no actions approved"
+		return m, nil
+	}
+	m.status = fmt.Sprintf("executing %d action(s)...", len(approved))
+	cmd := executeActions(m.provider, m.boardID, &m.kanban, approved)
+	m.syncDrawerCard(true)
+	return m, cmd
+}
+
+// editAction changes the action under the review cursor and approves it.
+func (m *Model) editAction(edit func(*agentAction)) {
+	item := m.drawer.review.Current()
+	if item == nil {
+		return
+	}
+	edit(&item.action)
+	item.label = describeAction(item.action, &m.kanban)
+	item.state = reviewApproved
+	m.status = "action edited"
+}
+
 func (m Model) sendAgentPrompt(prompt string) (tea.Model, tea.Cmd) {
 	if m.runningAsk {
 		m.status = "agent request in progress..."
@@ -576,10 +1460,13 @@ func (m Model) sendAgentPrompt(prompt string) (tea.Model, tea.Cmd) {
 	}
 
 	boardContext := m.currentBoardContext()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelAsk = cancel
 	m.runningAsk = true
 	m.status = fmt.Sprintf("running %s...", m.active)
 	m.pendingPrompt = prompt
 	m.drawer.AppendTimeline("you", prompt)
+	m.drawer.BeginStream(string(m.active))
 	m.prompt.input.SetValue("")
 
 	// open drawer if not already open
@@ -589,12 +1476,138 @@ func (m Model) sendAgentPrompt(prompt string) (tea.Model, tea.Cmd) {
 	}
 	m.focus = focusDrawer
 
-	return m, askAgentCmd(m.runner, m.active, boardContext, prompt)
+	return m, tea.Batch(askAgentCmd(ctx, m.runner, m.active, boardContext, prompt), m.drawer.spinner.Tick)
+}
+
+// cancelAgent kills the in-flight agent run. the stream still reports back
+// through agentResponseMsg, which records the cancellation.
+func (m *Model) cancelAgent() {
+	m.escArmed = false
+	if !m.runningAsk || m.cancelAsk == nil {
+		m.status = "no agent running"
+		return
+	}
+	m.cancelAsk()
+	m.status = "cancelling agent..."
+}
+
+// reloadCmd refreshes the board after a change: only what changed when the
+// provider can sync incrementally, with a full reload every fullReloadEvery.
+func (m *Model) reloadCmd() tea.Cmd {
+	s, ok := m.provider.(board.Syncer)
+	if ok && m.syncCursor != "" && time.Since(m.lastFullLoad) < fullReloadEvery {
+		return syncBoardCmd(s, m.boardID, m.syncCursor)
+	}
+	return m.loadBoardCmd(m.boardID, m.boardName)
+}
+
+// loadBoardCmd loads a board in full. labels and members only come along
+// when it isn't the board already shown.
+func (m *Model) loadBoardCmd(boardID, boardName string) tea.Cmd {
+	meta := boardID != m.boardID || m.kanban.lists == nil
+	return loadBoardDataCmd(m.provider, boardID, boardName, meta)
+}
+
+// rollback reverts a failed optimistic change, returning a note for the
+// timeline.
+func (m *Model) rollback(p *pendingChange) string {
+	if p == nil || p.rollback == nil {
+		return ""
+	}
+	p.rollback(&m.kanban)
+	m.syncDrawerCard(true)
+	return " (rolled back)"
+}
+
+// settle confirms an optimistic change once the provider accepted it.
+func (m *Model) settle(p *pendingChange, id string) {
+	if p != nil && p.tempID != "" {
+		m.kanban.settle(p.tempID, id)
+	}
+}
+
+// syncDrawerCard points the drawer at the fresh copy of its card, or clears
+// it when the card is no longer on the board.
+func (m *Model) syncDrawerCard(sameBoard bool) {
+	open := m.drawer.card
+	if open == nil {
+		return
+	}
+	fresh := m.kanban.findCard(open.ID)
+	if fresh == nil && sameBoard {
+		m.drawer.AppendTimeline("system", fmt.Sprintf("%q is no longer on the board", open.Name))
+	}
+	m.drawer.SetCard(fresh)
+	if fresh != nil {
+		m.drawer.SetChecklists(m.kanban.checklists[fresh.ID])
+		m.drawer.SetMembers(m.kanban.memberList(fresh.MemberIDs))
+	}
+}
+
+// checklistsCmd loads the drawer card's checklists when they haven't been,
+// or no longer match the card's progress; force loads them regardless.
+func (m *Model) checklistsCmd(force bool) tea.Cmd {
+	card := m.drawer.card
+	c, ok := m.provider.(board.Checklister)
+	if card == nil || !ok || isPending(card.ID) {
+		return nil
+	}
+	if !force && !m.kanban.checklistsStale(card) {
+		return nil
+	}
+	return loadChecklistsCmd(c, card.ID)
 }
 
+// activityCmd loads the provider's comments and history for the drawer's
+// card, when cardID is that card.
+func (m *Model) activityCmd(cardID string) tea.Cmd {
+	r, ok := m.provider.(board.ActivityReader)
+	if !ok || m.drawer.card == nil || m.drawer.card.ID != cardID || isPending(cardID) {
+		return nil
+	}
+	return loadActivityCmd(r, cardID)
+}
+
+// checklistsChanged reloads the drawer's checklists after a checklist
+// mutation on its card, whether it went through or not.
+func (m *Model) checklistsChanged(action, cardID string) tea.Cmd {
+	switch action {
+	case "check item", "add item", "convert item":
+		if m.drawer.card != nil && m.drawer.card.ID == cardID {
+			return m.checklistsCmd(true)
+		}
+	}
+	return nil
+}
+
+// undoLast reverts the most recent recorded mutation.
+func (m *Model) undoLast() tea.Cmd {
+	e, ok := m.undo.Pop()
+	if !ok {
+		m.status = "nothing to undo"
+		return nil
+	}
+	m.status = "undoing: " + e.label
+	return undoCmd(m.provider, e)
+}
+
+// restorePendingPrompt puts the last prompt back in the bar after a failed or
+// cancelled run, unless the user already typed something new.
+func (m *Model) restorePendingPrompt() {
+	if strings.TrimSpace(m.pendingPrompt) != "" && strings.TrimSpace(m.prompt.Value()) == "" {
+		m.prompt.input.SetValue(m.pendingPrompt)
+	}
+	m.pendingPrompt = ""
+}
+
+// maxContextComments caps the card comments sent to agents.
+const maxContextComments = 5
+
 func (m *Model) currentBoardContext() string {
+	now := time.Now()
 	var parts []string
 	parts = append(parts, fmt.Sprintf("Board: %s (id: %s)", m.boardName, m.boardID))
+	parts = append(parts, "Now: "+now.Format("Mon 2006-01-02 15:04"))
 
 	// card context if available
 	card := m.kanban.contextCard
@@ -610,38 +1623,153 @@ func (m *Model) currentBoardContext() string {
 			"",
 			fmt.Sprintf("Selected Card: %s (id: %s)", card.Name, card.ID),
 			fmt.Sprintf("List: %s", card.ListName),
+		)
+		if len(card.Labels) > 0 {
+			names := make([]string, len(card.Labels))
+			for i, l := range card.Labels {
+				names[i] = labelName(l)
+			}
+			parts = append(parts, "Labels: "+strings.Join(names, ", "))
+		}
+		if len(card.MemberIDs) > 0 {
+			parts = append(parts, "Members: "+m.kanban.memberList(card.MemberIDs))
+		}
+		if card.Start != nil {
+			parts = append(parts, "Start: "+dueInput(*card.Start))
+		}
+		if card.Due != nil {
+			status := dueText(*card.Due, false, now)
+			if card.DueComplete {
+				status = "done"
+			}
+			parts = append(parts, fmt.Sprintf("Due: %s (%s)", dueInput(*card.Due), status))
+		}
+		if lists, ok := m.kanban.checklists[card.ID]; ok {
+			for _, l := range lists {
+				total, checked := checklistTotals([]board.Checklist{l})
+				parts = append(parts, fmt.Sprintf("Checklist: %s (id: %s, %d/%d done)", l.Name, l.ID, checked, total))
+				for _, it := range l.Items {
+					box := "[ ]"
+					if it.Checked {
+						box = "[x]"
+					}
+					parts = append(parts, fmt.Sprintf("  %s %s (id: %s)", box, it.Name, it.ID))
+				}
+			}
+		} else if card.CheckItems > 0 {
+			parts = append(parts, fmt.Sprintf("Checklists: %d/%d items done", card.CheckItemsChecked, card.CheckItems))
+		}
+		parts = append(parts,
 			fmt.Sprintf("URL: %s", card.ShortURL),
 			"Description:",
 			desc,
 		)
+		if comments := m.recentComments(card.ID); len(comments) > 0 {
+			parts = append(parts, "Recent comments (oldest first):")
+			for _, c := range comments {
+				parts = append(parts, fmt.Sprintf("  - %s, %s: %s", c.Author, c.At.Local().Format("2006-01-02 15:04"), ellipsis(oneLine(c.Text), 300)))
+			}
+		}
 	}
 
-	// all lists with IDs and card counts
+	if e := m.undo.Peek(); e != nil {
+		parts = append(parts, "", "Last change (reverted by undo): "+e.label)
+	}
+
+	// all lists with IDs and card counts; a filter narrows what agents see
+	// to what the user sees
+	filtered := m.kanban.filter.Active()
+	if filtered {
+		parts = append(parts, "", fmt.Sprintf("Filter: %s (the user only sees matching cards; counts and cards below are filtered)", m.kanban.filter.String()))
+	}
 	parts = append(parts, "", "Lists:")
 	for _, list := range m.kanban.lists {
 		cards := m.kanban.cards[list.ID]
+		if filtered {
+			shown := 0
+			for _, c := range cards {
+				if m.kanban.shown(c) {
+					shown++
+				}
+			}
+			parts = append(parts, fmt.Sprintf("  - %s (id: %s, %d of %d cards match)", list.Name, list.ID, shown, len(cards)))
+			continue
+		}
 		parts = append(parts, fmt.Sprintf("  - %s (id: %s, %d cards)", list.Name, list.ID, len(cards)))
 	}
 
+	if filtered {
+		cards := m.kanban.filteredCards()
+		parts = append(parts, "", "Matching cards:")
+		for _, c := range cards[:min(len(cards), maxContextFiltered)] {
+			parts = append(parts, fmt.Sprintf("  - %s (id: %s, list: %s)", c.Name, c.ID, c.ListName))
+		}
+		if len(cards) > maxContextFiltered {
+			parts = append(parts, fmt.Sprintf("  ... and %d more", len(cards)-maxContextFiltered))
+		}
+	}
+
+	if len(m.kanban.labels) > 0 {
+		parts = append(parts, "", "Labels:")
+		for _, l := range m.kanban.labels {
+			parts = append(parts, fmt.Sprintf("  - %s (color: %s, id: %s)", labelName(l), l.Color, l.ID))
+		}
+	}
+
+	if len(m.kanban.members) > 0 {
+		parts = append(parts, "", "Members:")
+		for _, mem := range m.kanban.members {
+			you := ""
+			if mem.ID == m.kanban.me {
+				you = ", the user"
+			}
+			parts = append(parts, fmt.Sprintf("  - %s (@%s, id: %s%s)", memberName(mem), mem.Username, mem.ID, you))
+		}
+	}
+
+	if due := m.kanban.dueCards(); len(due) > 0 {
+		parts = append(parts, "", "Due dates (open cards, soonest first):")
+		for _, c := range due {
+			parts = append(parts, fmt.Sprintf("  - %s (id: %s, list: %s): %s, %s",
+				c.Name, c.ID, c.ListName, dueInput(*c.Due), dueText(*c.Due, false, now)))
+		}
+	}
+
 	return strings.Join(parts, "\n")
 }
 
+// recentComments returns the last maxContextComments comments on a card, if
+// its activity is loaded in the drawer.
+func (m *Model) recentComments(cardID string) []board.Activity {
+	if m.drawer.card == nil || m.drawer.card.ID != cardID {
+		return nil
+	}
+	var comments []board.Activity
+	for _, a := range m.drawer.activity {
+		if a.Kind == "comment" {
+			comments = append(comments, a)
+		}
+	}
+	return comments[max(0, len(comments)-maxContextComments):]
+}
+
 // --- view ---
 
 func (m Model) View() string {
-	if !m.trello.CanAuth() {
-		return baseStyle.Render(strings.Join([]string{
-			"trello auth not configured.",
-			"",
-			"set these env vars:",
-			"  TRELLO_API_KEY",
-			"  TRELLO_API_TOKEN",
-			"  TRELLO_BOARD_ID (optional)",
-		}, "\n"))
+	if !m.provider.CanAuth() {
+		lines := []string{m.provider.Name() + " auth not configured."}
+		if h, ok := m.provider.(board.AuthHelper); ok {
+			lines = append(lines, "")
+			lines = append(lines, h.AuthHelp()...)
+		}
+		return baseStyle.Render(strings.Join(lines, "\n"))
 	}
 
 	if m.mode == modeBoardSelect {
 		content := m.renderBoardSelect()
+		if m.search.visible {
+			return m.search.Render(&m.kanban, m.width, m.height)
+		}
 		if m.help.visible {
 			return m.help.Render(content, m.width, m.height)
 		}
@@ -649,6 +1777,10 @@ func (m Model) View() string {
 	}
 
 	header := m.header()
+	if m.editor.visible {
+		content := lipgloss.JoinVertical(lipgloss.Left, header, m.editor.View())
+		return clipToLineCount(content, max(8, m.height))
+	}
 	promptBar := m.prompt.View()
 
 	var main string
@@ -664,6 +1796,12 @@ func (m Model) View() string {
 	content := lipgloss.JoinVertical(lipgloss.Left, header, main, promptBar)
 	content = clipToLineCount(content, max(8, m.height))
 
+	if m.undo.visible {
+		return m.undo.Render(m.width, m.height)
+	}
+	if m.search.visible {
+		return m.search.Render(&m.kanban, m.width, m.height)
+	}
 	if m.help.visible {
 		return m.help.Render(content, m.width, m.height)
 	}
@@ -692,14 +1830,26 @@ func (m *Model) headerLeft() string {
 	} else if m.boardID != "" {
 		board = "board: " + shortID(m.boardID)
 	}
+	line := subtleStyle.Render(board)
+	if m.kanban.filter.Active() && m.mode == modeKanban {
+		// the left half of the header, less the board name
+		room := max(40, m.width-2)/2 - lipgloss.Width(board) - len("  filter: ")
+		line += "  " + filterStyle.Render("filter: "+ellipsis(m.kanban.filter.String(), max(10, room)))
+	}
 	return lipgloss.JoinVertical(lipgloss.Left,
 		heroStyle.Render("aboard"),
-		subtleStyle.Render(board),
+		line,
 	)
 }
 
 func (m *Model) headerRight() string {
-	agentLabel := "agent:" + string(m.active)
+	agentLabel := "agent: none"
+	if m.active != "" {
+		agentLabel = "agent:" + string(m.active)
+		if len(m.agents) > 1 {
+			agentLabel += fmt.Sprintf(" (%d/%d)", m.agentIndex()+1, len(m.agents))
+		}
+	}
 	status := m.status
 	if m.errText != "" {
 		status = m.errText
@@ -722,6 +1872,8 @@ func (m *Model) recalcLayout() {
 	mainHeight := max(6, m.height-headerHeight-promptHeight-2)
 
 	m.prompt.Resize(m.width)
+	// the editor takes the board and prompt bar's room
+	m.editor.Resize(m.width, mainHeight+promptHeight)
 
 	if m.drawerOpen {
 		kanbanWidth := max(30, int(float64(m.width)*0.4))
@@ -737,12 +1889,48 @@ func (m *Model) recalcLayout() {
 
 // --- helpers ---
 
-func (m *Model) toggleAgent() {
-	if m.active == agent.AgentCodex {
-		m.setAgent(agent.AgentClaude)
-	} else {
-		m.setAgent(agent.AgentCodex)
+func (m *Model) agentIndex() int {
+	for i, name := range m.agents {
+		if name == m.active {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Model) cycleAgent() {
+	if len(m.agents) == 0 {
+		m.status = "no agents configured"
+		return
+	}
+	m.setAgent(m.agents[(m.agentIndex()+1)%len(m.agents)])
+}
+
+func (m *Model) pickAgent(idx int) {
+	if idx < 0 || idx >= len(m.agents) {
+		m.status = fmt.Sprintf("no agent #%d (%d configured)", idx+1, len(m.agents))
+		return
+	}
+	m.setAgent(m.agents[idx])
+}
+
+// selectAgentByName handles "/agent <name>"; without a name it lists the profiles.
+func (m *Model) selectAgentByName(name string) {
+	if name == "" {
+		names := make([]string, 0, len(m.agents))
+		for i, a := range m.agents {
+			names = append(names, fmt.Sprintf("%d:%s", i+1, a))
+		}
+		m.status = "agents: " + strings.Join(names, "  ")
+		return
+	}
+	for _, a := range m.agents {
+		if strings.EqualFold(string(a), name) {
+			m.setAgent(a)
+			return
+		}
 	}
+	m.status = fmt.Sprintf("unknown agent %q", name)
 }
 
 func (m *Model) setAgent(next agent.AgentName) {
@@ -759,11 +1947,11 @@ func (m *Model) refreshData() tea.Cmd {
 
 	if m.mode == modeBoardSelect {
 		m.status = "refreshing boards..."
-		return loadBoardsCmd(m.trello)
+		return loadBoardsCmd(m.provider)
 	}
 	if m.boardID != "" {
 		m.status = "refreshing board..."
-		return loadBoardDataCmd(m.trello, m.boardID, m.boardName)
+		return m.loadBoardCmd(m.boardID, m.boardName)
 	}
 	return m.openBoardSelector()
 }
@@ -778,7 +1966,7 @@ func (m *Model) openBoardSelector() tea.Cmd {
 	m.errText = ""
 	m.mode = modeBoardSelect
 	m.status = "loading boards..."
-	return loadBoardsCmd(m.trello)
+	return loadBoardsCmd(m.provider)
 }
 
 func (m *Model) boardNameByID(boardID string) string {
@@ -0,0 +1,398 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// mutations are applied to the kanban model as soon as they're issued. the
+// result message carries the pendingChange so a failure can be rolled back
+// and a created item's placeholder id swapped for the real one.
+
+const pendingPrefix = "pending-"
+
+var pendingSeq int
+
+type pendingChange struct {
+	rollback func(*KanbanModel)
+	tempID   string
+}
+
+func newPendingID() string {
+	pendingSeq++
+	return fmt.Sprintf("%s%d", pendingPrefix, pendingSeq)
+}
+
+func isPending(id string) bool {
+	return strings.HasPrefix(id, pendingPrefix)
+}
+
+// withPending attaches p to the mutation message cmd returns.
+func withPending(k *KanbanModel, cmd tea.Cmd, p *pendingChange) tea.Cmd {
+	k.edits++
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case cardMutatedMsg:
+			msg.pending = p
+			return msg
+		case listMutatedMsg:
+			msg.pending = p
+			return msg
+		default:
+			return msg
+		}
+	}
+}
+
+func moveCard(client board.BoardProvider, k *KanbanModel, cardID, listID string) tea.Cmd {
+	prev := k.findCard(cardID)
+	return withPending(k, moveCardCmd(client, cardID, listID, prev), k.applyMove(cardID, listID))
+}
+
+func updateCard(client board.BoardProvider, k *KanbanModel, cardID string, fields board.CardUpdate) tea.Cmd {
+	prev := k.findCard(cardID)
+	return withPending(k, updateCardCmd(client, cardID, fields, prev), k.applyUpdate(cardID, fields))
+}
+
+func archiveCard(client board.BoardProvider, k *KanbanModel, cardID string) tea.Cmd {
+	prev := k.findCard(cardID)
+	return withPending(k, archiveCardCmd(client, cardID, prev), k.applyArchiveCard(cardID))
+}
+
+func createCard(client board.BoardProvider, k *KanbanModel, listID, name string) tea.Cmd {
+	return withPending(k, createCardCmd(client, listID, name), k.applyCreateCard(listID, name))
+}
+
+func createList(client board.BoardProvider, k *KanbanModel, boardID, name string) tea.Cmd {
+	return withPending(k, createListCmd(client, boardID, name), k.applyCreateList(name))
+}
+
+func archiveList(client board.BoardProvider, k *KanbanModel, listID string) tea.Cmd {
+	prev := k.findList(listID)
+	return withPending(k, archiveListCmd(client, listID, prev), k.applyArchiveList(listID))
+}
+
+// labelCard does nothing when the card already has (or lacks) the label;
+// trello rejects adding one twice.
+func labelCard(client board.BoardProvider, k *KanbanModel, cardID string, label board.Label, add bool) tea.Cmd {
+	prev := k.findCard(cardID)
+	if prev != nil && (labelIndex(prev.Labels, label.ID) >= 0) == add {
+		return nil
+	}
+	return withPending(k, labelCardCmd(client, cardID, label, add, prev), k.applyLabel(cardID, label, add))
+}
+
+func setCardDue(client board.BoardProvider, k *KanbanModel, cardID string, due *time.Time) tea.Cmd {
+	prev := k.findCard(cardID)
+	return withPending(k, setDueCmd(client, cardID, due, prev), k.applyDue(cardID, func(c *board.Card) { c.Due = due }))
+}
+
+func setCardDueComplete(client board.BoardProvider, k *KanbanModel, cardID string, complete bool) tea.Cmd {
+	prev := k.findCard(cardID)
+	return withPending(k, dueCompleteCmd(client, cardID, complete, prev), k.applyDue(cardID, func(c *board.Card) { c.DueComplete = complete }))
+}
+
+func assignMember(client board.BoardProvider, k *KanbanModel, cardID string, member board.Member, add bool) tea.Cmd {
+	prev := k.findCard(cardID)
+	if prev != nil && slices.Contains(prev.MemberIDs, member.ID) == add {
+		return nil
+	}
+	return withPending(k, assignMemberCmd(client, cardID, member, add, prev), k.applyMember(cardID, member.ID, add))
+}
+
+// checkItem sets an item's state, keeping the card's progress and the cached
+// checklists in step. nothing is sent when the item is already in that state.
+func checkItem(client board.BoardProvider, k *KanbanModel, cardID string, item board.CheckItem, checked bool) tea.Cmd {
+	if item.Checked == checked {
+		return nil
+	}
+	return withPending(k, checkItemCmd(client, cardID, item, checked), k.applyCheck(cardID, item.ID, checked))
+}
+
+func (k *KanbanModel) applyMove(cardID, listID string) *pendingChange {
+	list := k.findList(listID)
+	from, idx, ok := k.cardPos(cardID)
+	if list == nil || !ok || from == listID {
+		return nil
+	}
+	followed := k.selectedID() == cardID
+	card := k.removeCard(cardID)
+	card.IDList, card.ListName = list.ID, list.Name
+	k.insertCard(card, len(k.cards[listID]))
+	if followed {
+		k.selectCard(cardID)
+	}
+	fromName := k.listName(from)
+	return &pendingChange{rollback: func(k *KanbanModel) {
+		if k.findList(from) == nil || k.findCard(cardID) == nil {
+			return
+		}
+		refollow := k.selectedID() == cardID
+		card := k.removeCard(cardID)
+		card.IDList, card.ListName = from, fromName
+		k.insertCard(card, idx)
+		if refollow {
+			k.selectCard(cardID)
+		}
+	}}
+}
+
+func (k *KanbanModel) applyUpdate(cardID string, fields board.CardUpdate) *pendingChange {
+	card := k.cardRef(cardID)
+	if card == nil {
+		return nil
+	}
+	prev := *card
+	if fields.Name != nil {
+		card.Name = *fields.Name
+	}
+	if fields.Desc != nil {
+		card.Desc = *fields.Desc
+	}
+	return &pendingChange{rollback: func(k *KanbanModel) {
+		card := k.cardRef(cardID)
+		if card == nil {
+			return
+		}
+		if fields.Name != nil {
+			card.Name = prev.Name
+		}
+		if fields.Desc != nil {
+			card.Desc = prev.Desc
+		}
+	}}
+}
+
+func (k *KanbanModel) applyLabel(cardID string, label board.Label, add bool) *pendingChange {
+	card := k.cardRef(cardID)
+	if card == nil {
+		return nil
+	}
+	prev := card.Labels
+	i := labelIndex(prev, label.ID)
+	switch {
+	case add && i < 0:
+		card.Labels = append(slices.Clip(prev), label)
+	case !add && i >= 0:
+		card.Labels = slices.Delete(slices.Clone(prev), i, i+1)
+	default:
+		return nil
+	}
+	return &pendingChange{rollback: func(k *KanbanModel) {
+		if card := k.cardRef(cardID); card != nil {
+			card.Labels = prev
+		}
+	}}
+}
+
+// applyDue edits a card's due date or completion; rollback restores both.
+func (k *KanbanModel) applyDue(cardID string, edit func(*board.Card)) *pendingChange {
+	card := k.cardRef(cardID)
+	if card == nil {
+		return nil
+	}
+	due, complete := card.Due, card.DueComplete
+	edit(card)
+	return &pendingChange{rollback: func(k *KanbanModel) {
+		if card := k.cardRef(cardID); card != nil {
+			card.Due, card.DueComplete = due, complete
+		}
+	}}
+}
+
+func (k *KanbanModel) applyMember(cardID, memberID string, add bool) *pendingChange {
+	card := k.cardRef(cardID)
+	if card == nil {
+		return nil
+	}
+	prev := card.MemberIDs
+	i := slices.Index(prev, memberID)
+	switch {
+	case add && i < 0:
+		card.MemberIDs = append(slices.Clip(prev), memberID)
+	case !add && i >= 0:
+		card.MemberIDs = slices.Delete(slices.Clone(prev), i, i+1)
+	default:
+		return nil
+	}
+	return &pendingChange{rollback: func(k *KanbanModel) {
+		if card := k.cardRef(cardID); card != nil {
+			card.MemberIDs = prev
+		}
+	}}
+}
+
+func (k *KanbanModel) applyCheck(cardID, itemID string, checked bool) *pendingChange {
+	card := k.cardRef(cardID)
+	if card == nil {
+		return nil
+	}
+	delta := 1
+	if !checked {
+		delta = -1
+	}
+	card.CheckItemsChecked += delta
+	set := func(k *KanbanModel, v bool) {
+		if _, it := k.findCheckItem(cardID, itemID); it != nil {
+			it.Checked = v
+		}
+	}
+	set(k, checked)
+	return &pendingChange{rollback: func(k *KanbanModel) {
+		if card := k.cardRef(cardID); card != nil {
+			card.CheckItemsChecked -= delta
+		}
+		set(k, !checked)
+	}}
+}
+
+func (k *KanbanModel) applyArchiveCard(cardID string) *pendingChange {
+	_, idx, ok := k.cardPos(cardID)
+	if !ok {
+		return nil
+	}
+	card := k.removeCard(cardID)
+	return &pendingChange{rollback: func(k *KanbanModel) {
+		if k.findList(card.IDList) != nil && k.findCard(cardID) == nil {
+			k.insertCard(card, idx)
+		}
+	}}
+}
+
+func (k *KanbanModel) applyCreateCard(listID, name string) *pendingChange {
+	list := k.findList(listID)
+	if list == nil {
+		return nil
+	}
+	id := newPendingID()
+	k.insertCard(board.Card{ID: id, Name: name, IDList: list.ID, ListName: list.Name}, len(k.cards[listID]))
+	return &pendingChange{tempID: id, rollback: func(k *KanbanModel) {
+		if k.findCard(id) != nil {
+			k.removeCard(id)
+		}
+	}}
+}
+
+func (k *KanbanModel) applyCreateList(name string) *pendingChange {
+	id := newPendingID()
+	k.lists = append(k.lists, board.List{ID: id, Name: name})
+	return &pendingChange{tempID: id, rollback: func(k *KanbanModel) {
+		k.removeList(id)
+	}}
+}
+
+func (k *KanbanModel) applyArchiveList(listID string) *pendingChange {
+	idx := k.listIndex(listID)
+	if idx < 0 {
+		return nil
+	}
+	list, cards, cursor := k.lists[idx], k.cards[listID], k.cardCursors[listID]
+	k.removeList(listID)
+	return &pendingChange{rollback: func(k *KanbanModel) {
+		if k.listIndex(listID) >= 0 {
+			return
+		}
+		idx := min(idx, len(k.lists))
+		k.lists = append(k.lists[:idx:idx], append([]board.List{list}, k.lists[idx:]...)...)
+		k.cards[listID] = cards
+		k.cardCursors[listID] = cursor
+		if k.listCursor >= idx && len(k.lists) > 1 {
+			k.listCursor = min(k.listCursor+1, len(k.lists)-1)
+		}
+	}}
+}
+
+// settle swaps a created item's placeholder id for the one the provider
+// assigned.
+func (k *KanbanModel) settle(tempID, id string) {
+	if id == "" {
+		return
+	}
+	if card := k.cardRef(tempID); card != nil {
+		card.ID = id
+		return
+	}
+	if i := k.listIndex(tempID); i >= 0 {
+		k.lists[i].ID = id
+		k.cards[id] = k.cards[tempID]
+		k.cardCursors[id] = k.cardCursors[tempID]
+		delete(k.cards, tempID)
+		delete(k.cardCursors, tempID)
+	}
+}
+
+// --- helpers ---
+
+func (k *KanbanModel) selectedID() string {
+	if c := k.SelectedCard(); c != nil {
+		return c.ID
+	}
+	return ""
+}
+
+func (k *KanbanModel) selectCard(cardID string) {
+	listID, idx, ok := k.cardPos(cardID)
+	if !ok {
+		return
+	}
+	k.listCursor = k.listIndex(listID)
+	k.cardCursors[listID] = idx
+	k.ensureHorizontalScroll()
+}
+
+// cardRef points into the card slices so edits stick.
+func (k *KanbanModel) cardRef(cardID string) *board.Card {
+	listID, idx, ok := k.cardPos(cardID)
+	if !ok {
+		return nil
+	}
+	return &k.cards[listID][idx]
+}
+
+func (k *KanbanModel) cardPos(cardID string) (string, int, bool) {
+	for _, l := range k.lists {
+		if i := cardIndex(k.cards[l.ID], cardID); i >= 0 {
+			return l.ID, i, true
+		}
+	}
+	return "", 0, false
+}
+
+func (k *KanbanModel) removeCard(cardID string) board.Card {
+	listID, idx, _ := k.cardPos(cardID)
+	cards := k.cards[listID]
+	card := cards[idx]
+	k.cards[listID] = append(cards[:idx:idx], cards[idx+1:]...)
+	if k.cardCursors[listID] > idx || k.cardCursors[listID] >= len(k.cards[listID]) {
+		k.cardCursors[listID] = max(0, k.cardCursors[listID]-1)
+	}
+	return card
+}
+
+func (k *KanbanModel) insertCard(card board.Card, idx int) {
+	cards := k.cards[card.IDList]
+	idx = max(0, min(idx, len(cards)))
+	k.cards[card.IDList] = append(cards[:idx:idx], append([]board.Card{card}, cards[idx:]...)...)
+	if idx <= k.cardCursors[card.IDList] && len(cards) > 0 {
+		k.cardCursors[card.IDList]++
+	}
+}
+
+func (k *KanbanModel) removeList(listID string) {
+	idx := k.listIndex(listID)
+	if idx < 0 {
+		return
+	}
+	k.lists = append(k.lists[:idx:idx], k.lists[idx+1:]...)
+	delete(k.cards, listID)
+	delete(k.cardCursors, listID)
+	if k.listCursor > idx || k.listCursor >= len(k.lists) {
+		k.listCursor = max(0, k.listCursor-1)
+	}
+	k.ensureHorizontalScroll()
+}
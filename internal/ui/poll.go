@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// background polling picks up teammates' changes every cfg.PollInterval, or
+// whenever a board.Watcher pushes one. it goes through the same reload as a
+// mutation, but the result is applied quietly: no status or mode changes, the
+// selection stays put, and cards that came back different are highlighted
+// until the cursor lands on them.
+
+type pollTickMsg struct{}
+
+func pollCmd(interval time.Duration) tea.Cmd {
+	if interval <= 0 {
+		return nil
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg { return pollTickMsg{} })
+}
+
+// asPoll marks the load or sync message cmd returns as coming from a poll.
+func asPoll(cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case boardDataLoadedMsg:
+			msg.poll = true
+			return msg
+		case boardSyncedMsg:
+			msg.poll = true
+			return msg
+		default:
+			return msg
+		}
+	}
+}
+
+// startPoll kicks off a background reload unless the board isn't on screen.
+// one already running is asked to go again when it's done, since it may have
+// started before the change that prompted this one.
+func (m *Model) startPoll() tea.Cmd {
+	if m.loading || m.mode != modeKanban || m.boardID == "" {
+		return nil
+	}
+	if m.polling {
+		m.pollQueued = true
+		return nil
+	}
+	m.polling = true
+	m.pollEdits = m.kanban.edits
+	return asPoll(m.reloadCmd())
+}
+
+// finishPoll ends a poll, starting the queued one if there is one.
+func (m *Model) finishPoll() tea.Cmd {
+	m.polling = false
+	if !m.pollQueued {
+		return nil
+	}
+	m.pollQueued = false
+	return m.startPoll()
+}
+
+// pollDropped reports whether a poll result can't be applied: the board is
+// gone, or a local edit went out while it was in flight. the latter is
+// retried so a pushed change isn't lost.
+func (m *Model) pollDropped(boardID string) bool {
+	if boardID != m.boardID || m.mode != modeKanban {
+		m.pollQueued = false
+		return true
+	}
+	if m.kanban.edits != m.pollEdits {
+		m.pollQueued = true
+		return true
+	}
+	return false
+}
+
+func (m Model) applyPolledData(msg boardDataLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil || m.pollDropped(msg.boardID) {
+		return m, m.finishPoll()
+	}
+	before := m.kanban.snapshot()
+	m.kanban.Refresh(msg.lists, msg.cards)
+	if msg.meta {
+		m.kanban.labels = msg.labels
+		m.kanban.members, m.kanban.me = msg.members, msg.me
+	}
+	m.kanban.markChanged(before)
+	m.syncDrawerCard(true)
+	m.syncCursor = msg.cursor
+	m.lastFullLoad = time.Now()
+	return m, tea.Batch(m.finishPoll(), m.checklistsCmd(false))
+}
+
+func (m Model) applyPolledSync(msg boardSyncedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil || m.pollDropped(msg.boardID) {
+		return m, m.finishPoll()
+	}
+	if msg.changes.Reload {
+		return m, asPoll(m.loadBoardCmd(m.boardID, m.boardName))
+	}
+	before := m.kanban.snapshot()
+	m.kanban.Patch(msg.changes)
+	m.kanban.markChanged(before)
+	m.syncCursor = msg.changes.Cursor
+	m.syncDrawerCard(true)
+	return m, tea.Batch(m.finishPoll(), m.checklistsCmd(false))
+}
+
+// watchBoard asks a provider that can push changes to watch the current
+// board, stopping the watch on the previous one.
+func (m *Model) watchBoard() tea.Cmd {
+	w, ok := m.provider.(board.Watcher)
+	if !ok || m.watching == m.boardID {
+		return nil
+	}
+	if m.cancelWatch != nil {
+		m.cancelWatch()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.watching, m.cancelWatch = m.boardID, cancel
+	return watchBoardCmd(ctx, w, m.boardID)
+}
+
+func (k *KanbanModel) snapshot() map[string]board.Card {
+	cards := make(map[string]board.Card)
+	for _, cs := range k.cards {
+		for _, c := range cs {
+			cards[c.ID] = c
+		}
+	}
+	return cards
+}
+
+// markChanged highlights cards that are new or differ from before. the
+// selected card is skipped: it's already being looked at.
+func (k *KanbanModel) markChanged(before map[string]board.Card) {
+	selected := k.selectedID()
+	for _, cs := range k.cards {
+		for _, c := range cs {
+			if prev, ok := before[c.ID]; ok && sameCard(prev, c) || c.ID == selected {
+				continue
+			}
+			if k.changed == nil {
+				k.changed = make(map[string]bool)
+			}
+			k.changed[c.ID] = true
+		}
+	}
+}
+
+// sameCard compares what a card row and the drawer show.
+func sameCard(a, b board.Card) bool {
+	return a.ID == b.ID && a.Name == b.Name && a.Desc == b.Desc && a.IDList == b.IDList &&
+		a.ListName == b.ListName && a.URL == b.URL && a.ShortURL == b.ShortURL &&
+		slices.Equal(a.Labels, b.Labels) && slices.Equal(a.MemberIDs, b.MemberIDs) && sameTime(a.Due, b.Due) && sameTime(a.Start, b.Start) &&
+		a.DueComplete == b.DueComplete && a.CheckItems == b.CheckItems && a.CheckItemsChecked == b.CheckItemsChecked
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// markSeen clears the highlight on the selected card.
+func (k *KanbanModel) markSeen() {
+	delete(k.changed, k.selectedID())
+}
@@ -5,7 +5,8 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
-	"github.com/codywilliamson/aboard/internal/trello"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/codywilliamson/aboard/internal/board"
 )
 
 type promptMode int
@@ -19,16 +20,35 @@ const (
 	promptNewList
 	promptConfirmArchiveCard
 	promptConfirmArchiveList
+	promptEditAction
+	promptLabels
+	promptMembers
+	promptDue
+	promptCheckItem
+	promptFilter
 )
 
 type PromptBar struct {
-	mode       promptMode
-	input      textinput.Model
-	focused    bool
-	width      int
-	lists      []trello.List
-	listCursor int
+	mode         promptMode
+	input        textinput.Model
+	focused      bool
+	width        int
+	lists        []board.List
+	listCursor   int
 	confirmLabel string
+
+	// toggle picker for labels and members: the board's options and which
+	// the card has
+	options      []pickOption
+	optionOn     map[string]bool
+	optionCursor int
+}
+
+// pickOption is an entry in the toggle picker.
+type pickOption struct {
+	id     string
+	name   string
+	marker string // rendered before the name, e.g. a label's colored dot
 }
 
 func NewPromptBar() PromptBar {
@@ -56,15 +76,72 @@ func (p *PromptBar) SetMode(mode promptMode) {
 		p.input.Placeholder = "new card name..."
 	case promptNewList:
 		p.input.Placeholder = "new list name..."
+	case promptEditAction:
+		p.input.Placeholder = "edit action..."
+	case promptDue:
+		p.input.Placeholder = "tomorrow 5pm, +3d, fri, none..."
+	case promptCheckItem:
+		p.input.Placeholder = "new checklist item..."
+	case promptFilter:
+		p.input.Placeholder = `label:bug @me due:overdue "login"...`
 	}
 }
 
-func (p *PromptBar) SetMoveLists(lists []trello.List, currentIdx int) {
+func (p *PromptBar) SetMoveLists(lists []board.List, currentIdx int) {
 	p.mode = promptMove
 	p.lists = lists
 	p.listCursor = currentIdx
 }
 
+func (p *PromptBar) SetLabels(labels []board.Label, card []board.Label) {
+	options := make([]pickOption, len(labels))
+	for i, l := range labels {
+		options[i] = pickOption{id: l.ID, name: labelName(l), marker: lipgloss.NewStyle().Foreground(labelColor(l)).Render("●")}
+	}
+	on := make([]string, len(card))
+	for i, l := range card {
+		on[i] = l.ID
+	}
+	p.setOptions(promptLabels, options, on)
+}
+
+func (p *PromptBar) SetMembers(members []board.Member, card []string, me string) {
+	options := make([]pickOption, len(members))
+	for i, m := range members {
+		name := memberName(m)
+		if m.ID == me {
+			name += " (me)"
+		}
+		options[i] = pickOption{id: m.ID, name: name, marker: memberStyle.Render(memberInitials(m))}
+	}
+	p.setOptions(promptMembers, options, card)
+}
+
+func (p *PromptBar) setOptions(mode promptMode, options []pickOption, on []string) {
+	p.mode = mode
+	p.options = options
+	p.optionOn = make(map[string]bool, len(on))
+	for _, id := range on {
+		p.optionOn[id] = true
+	}
+	p.optionCursor = 0
+}
+
+func (p *PromptBar) MoveOption(delta int) {
+	p.optionCursor = max(0, min(p.optionCursor+delta, len(p.options)-1))
+}
+
+// ToggleOption flips the option under the cursor, returning its id and
+// whether it is now on.
+func (p *PromptBar) ToggleOption() (string, bool, bool) {
+	if p.optionCursor < 0 || p.optionCursor >= len(p.options) {
+		return "", false, false
+	}
+	id := p.options[p.optionCursor].id
+	p.optionOn[id] = !p.optionOn[id]
+	return id, p.optionOn[id], true
+}
+
 func (p *PromptBar) SetConfirmArchiveCard(label string) {
 	p.mode = promptConfirmArchiveCard
 	p.confirmLabel = label
@@ -82,11 +159,16 @@ func (p *PromptBar) Prefill(text string) {
 
 func (p *PromptBar) Focus() {
 	p.focused = true
-	if p.mode != promptMove && p.mode != promptConfirmArchiveCard && p.mode != promptConfirmArchiveList {
+	if p.mode != promptMove && !p.picking() && p.mode != promptConfirmArchiveCard && p.mode != promptConfirmArchiveList {
 		p.input.Focus()
 	}
 }
 
+// picking reports whether the bar shows the toggle picker.
+func (p *PromptBar) picking() bool {
+	return p.mode == promptLabels || p.mode == promptMembers
+}
+
 func (p *PromptBar) Blur() {
 	p.focused = false
 	p.input.Blur()
@@ -103,6 +185,9 @@ func (p *PromptBar) Reset() {
 	p.lists = nil
 	p.listCursor = 0
 	p.confirmLabel = ""
+	p.options = nil
+	p.optionOn = nil
+	p.optionCursor = 0
 }
 
 func (p *PromptBar) Resize(w int) {
@@ -140,6 +225,10 @@ func (p *PromptBar) View() string {
 	switch p.mode {
 	case promptMove:
 		content = p.renderMoveView()
+	case promptLabels:
+		content = p.renderPickerView("labels", "no labels")
+	case promptMembers:
+		content = p.renderPickerView("members", "no members")
 	case promptConfirmArchiveCard:
 		content = p.renderConfirmView("archive card")
 	case promptConfirmArchiveList:
@@ -164,6 +253,14 @@ func (p *PromptBar) modeLabel() string {
 		return "card"
 	case promptNewList:
 		return "list"
+	case promptEditAction:
+		return "edit"
+	case promptDue:
+		return "due"
+	case promptCheckItem:
+		return "item"
+	case promptFilter:
+		return "filter"
 	default:
 		return "prompt"
 	}
@@ -185,6 +282,52 @@ func (p *PromptBar) renderMoveView() string {
 	return badge + " " + picker + hint
 }
 
+func (p *PromptBar) renderPickerView(title, empty string) string {
+	badge := promptModeBadgeStyle.Render(title)
+	var parts []string
+	for i, o := range p.options {
+		mark := " "
+		if p.optionOn[o.id] {
+			mark = "✓"
+		}
+		name := mark + ellipsis(o.name, 16)
+		if i == p.optionCursor {
+			parts = append(parts, o.marker+promptMoveSelectedStyle.Render("▸"+name+"◂"))
+		} else {
+			parts = append(parts, o.marker+promptMoveNormalStyle.Render(name))
+		}
+	}
+	hint := subtleStyle.Render("  space: toggle  enter: toggle + close  esc: done")
+	if len(parts) == 0 {
+		return badge + subtleStyle.Render(" "+empty) + hint
+	}
+	// boards can have more options than fit; keep the cursor's neighbours
+	room := p.width - 6 - lipgloss.Width(badge) - lipgloss.Width(hint)
+	from, to := p.optionCursor, p.optionCursor+1
+	used := lipgloss.Width(parts[p.optionCursor])
+	for grew := true; grew; {
+		grew = false
+		if to < len(parts) && used+1+lipgloss.Width(parts[to]) <= room {
+			used += 1 + lipgloss.Width(parts[to])
+			to++
+			grew = true
+		}
+		if from > 0 && used+1+lipgloss.Width(parts[from-1]) <= room {
+			from--
+			used += 1 + lipgloss.Width(parts[from])
+			grew = true
+		}
+	}
+	picker := strings.Join(parts[from:to], " ")
+	if from > 0 {
+		picker = "‹ " + picker
+	}
+	if to < len(parts) {
+		picker += " ›"
+	}
+	return badge + " " + picker + hint
+}
+
 func (p *PromptBar) renderConfirmView(action string) string {
 	badge := promptModeBadgeStyle.Render("archive")
 	label := fmt.Sprintf("%s %q?", action, p.confirmLabel)
@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/codywilliamson/aboard/internal/config"
+)
+
+type reviewState int
+
+const (
+	reviewPending reviewState = iota
+	reviewApproved
+	reviewRejected
+)
+
+type reviewItem struct {
+	action agentAction
+	label  string
+	state  reviewState
+}
+
+// reviewQueue holds agent actions waiting for the user to approve, reject or
+// edit them. only approved actions run.
+type reviewQueue struct {
+	items  []reviewItem
+	cursor int
+}
+
+// needsApproval reports whether a has to wait for review under policy.
+func needsApproval(policy string, a agentAction) bool {
+	switch policy {
+	case config.ApproveAuto:
+		return false
+	case config.ApproveAll:
+		return true
+	default:
+		return a.destructive()
+	}
+}
+
+func (q *reviewQueue) Active() bool {
+	return len(q.items) > 0
+}
+
+func (q *reviewQueue) Add(actions []agentAction, k *KanbanModel) {
+	for _, a := range actions {
+		q.items = append(q.items, reviewItem{action: a, label: describeAction(a, k)})
+	}
+}
+
+func (q *reviewQueue) Current() *reviewItem {
+	if q.cursor < 0 || q.cursor >= len(q.items) {
+		return nil
+	}
+	return &q.items[q.cursor]
+}
+
+func (q *reviewQueue) Move(delta int) {
+	q.cursor = min(max(0, q.cursor+delta), len(q.items)-1)
+}
+
+// Mark sets the current item's state and steps to the next one.
+func (q *reviewQueue) Mark(state reviewState) {
+	if item := q.Current(); item != nil {
+		item.state = state
+		q.Move(1)
+	}
+}
+
+func (q *reviewQueue) MarkAll(state reviewState) {
+	for i := range q.items {
+		q.items[i].state = state
+	}
+}
+
+// Take empties the queue, returning the approved actions and how many were
+// dropped. pending items count as rejected.
+func (q *reviewQueue) Take() ([]agentAction, int) {
+	var approved []agentAction
+	for _, item := range q.items {
+		if item.state == reviewApproved {
+			approved = append(approved, item.action)
+		}
+	}
+	dropped := len(q.items) - len(approved)
+	q.items = nil
+	q.cursor = 0
+	return approved, dropped
+}
+
+// Height is the number of lines View renders.
+func (q *reviewQueue) Height() int {
+	if !q.Active() {
+		return 0
+	}
+	return len(q.items) + 3
+}
+
+func (q *reviewQueue) View(width int) string {
+	title := lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("─── Review %d action(s) ───", len(q.items)))
+	// one line per row so Height stays exact
+	lines := []string{title}
+	for i, item := range q.items {
+		var mark string
+		switch item.state {
+		case reviewApproved:
+			mark = statusStyle.Render("[✓]")
+		case reviewRejected:
+			mark = errorStyle.Render("[✗]")
+		default:
+			mark = subtleStyle.Render("[ ]")
+		}
+		label := ellipsis(item.label, max(10, width-6))
+		if item.action.destructive() {
+			label = errorStyle.Render(label)
+		}
+		row := mark + " " + label
+		if i == q.cursor {
+			row = contextMarkerStyle.Render("▸") + row
+		} else {
+			row = " " + row
+		}
+		lines = append(lines, row)
+	}
+	hint := ellipsis("y/n: approve/reject  Y/N: all  e: edit  enter: run  esc: reject all", width)
+	lines = append(lines, subtleStyle.Render(hint), "")
+	return strings.Join(lines, "\n")
+}
+
+// describeAction renders an action with card and list names resolved from
+// the loaded board so the user can tell what it will touch.
+func describeAction(a agentAction, k *KanbanModel) string {
+	card := k.cardLabel(a.CardID)
+	list := k.listName(a.ListID)
+	switch a.Type {
+	case "move_card":
+		return fmt.Sprintf("move %s → %s", card, list)
+	case "update_card":
+		switch {
+		case a.Name != "" && a.Desc != "":
+			return fmt.Sprintf("rename %s to %q and replace its description", card, a.Name)
+		case a.Name != "":
+			return fmt.Sprintf("rename %s to %q", card, a.Name)
+		default:
+			return fmt.Sprintf("replace description of %s: %s", card, oneLine(a.Desc))
+		}
+	case "add_comment":
+		return fmt.Sprintf("comment on %s: %s", card, oneLine(a.Text))
+	case "archive_card":
+		return fmt.Sprintf("archive card %s", card)
+	case "create_card":
+		return fmt.Sprintf("create card %q in %s", a.Name, list)
+	case "create_list":
+		return fmt.Sprintf("create list %q", a.Name)
+	case "archive_list":
+		return fmt.Sprintf("archive list %s (%d cards)", list, len(k.cards[a.ListID]))
+	case "add_label":
+		return fmt.Sprintf("add label %s to %s", k.labelRef(a.Label), card)
+	case "remove_label":
+		return fmt.Sprintf("remove label %s from %s", k.labelRef(a.Label), card)
+	case "assign_member":
+		return fmt.Sprintf("assign %s to %s", k.memberRef(a.Member), card)
+	case "unassign_member":
+		return fmt.Sprintf("unassign %s from %s", k.memberRef(a.Member), card)
+	case "set_due":
+		return fmt.Sprintf("set due date of %s: %s", card, dueRef(a.Due))
+	case "add_checklist_item":
+		checklist := a.Checklist
+		if checklist == "" {
+			checklist = "its checklist"
+		}
+		return fmt.Sprintf("add %q to %s on %s", a.Text, checklist, card)
+	case "check_item":
+		verb := "check"
+		if !a.checkedState() {
+			verb = "uncheck"
+		}
+		return fmt.Sprintf("%s %s on %s", verb, k.checkItemRef(a.CardID, a.Item), card)
+	case "undo":
+		return "undo the last change"
+	default:
+		return "unknown action " + a.Type
+	}
+}
+
+func (k *KanbanModel) cardLabel(id string) string {
+	if c := k.findCard(id); c != nil {
+		return fmt.Sprintf("%q", c.Name)
+	}
+	if id == "" {
+		return "(no card)"
+	}
+	return "card " + shortID(id) + " (not on board)"
+}
+
+func (k *KanbanModel) listName(id string) string {
+	if l := k.findList(id); l != nil {
+		return l.Name
+	}
+	if id == "" {
+		return "(no list)"
+	}
+	return "list " + shortID(id) + " (not on board)"
+}
+
+// editText points at the text an edit replaces, or nil when the action has
+// none. moves are edited with the list picker instead.
+func (a *agentAction) editText() *string {
+	switch a.Type {
+	case "update_card":
+		if a.Name != "" {
+			return &a.Name
+		}
+		return &a.Desc
+	case "add_comment":
+		return &a.Text
+	case "create_card", "create_list":
+		return &a.Name
+	case "add_label", "remove_label":
+		return &a.Label
+	case "assign_member", "unassign_member":
+		return &a.Member
+	case "set_due":
+		return &a.Due
+	case "add_checklist_item":
+		return &a.Text
+	}
+	return nil
+}
+
+// dueRef shows how the review queue will read an action's due date.
+func dueRef(s string) string {
+	due, err := parseDue(s, time.Now())
+	switch {
+	case err != nil:
+		return fmt.Sprintf("%q (unreadable)", s)
+	case due == nil:
+		return "none"
+	default:
+		return formatDue(*due)
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/codywilliamson/aboard/internal/board"
+	"github.com/codywilliamson/aboard/internal/config"
+)
+
+func TestNeedsApproval(t *testing.T) {
+	all := []string{
+		"move_card", "update_card", "add_comment", "archive_card", "create_card", "create_list",
+		"archive_list", "add_label", "remove_label", "assign_member", "unassign_member",
+		"set_due", "add_checklist_item", "check_item", "undo",
+	}
+	destructive := map[string]bool{"archive_card": true, "archive_list": true, "undo": true}
+
+	for _, typ := range all {
+		a := agentAction{Type: typ}
+		if needsApproval(config.ApproveAuto, a) {
+			t.Errorf("auto: %s needs approval", typ)
+		}
+		if !needsApproval(config.ApproveAll, a) {
+			t.Errorf("all: %s runs without approval", typ)
+		}
+		if got := needsApproval(config.ApproveDestructive, a); got != destructive[typ] {
+			t.Errorf("destructive: %s needs approval = %v", typ, got)
+		}
+	}
+}
+
+func reviewBoard() KanbanModel {
+	return KanbanModel{
+		lists: []board.List{{ID: "l1", Name: "Todo"}, {ID: "l2", Name: "Done"}},
+		cards: map[string][]board.Card{
+			"l1": {{ID: "c1", Name: "Login", IDList: "l1"}},
+		},
+	}
+}
+
+func TestReviewQueueApproveReject(t *testing.T) {
+	k := reviewBoard()
+	var q reviewQueue
+	q.Add([]agentAction{
+		{Type: "move_card", CardID: "c1", ListID: "l2"},
+		{Type: "archive_card", CardID: "c1"},
+		{Type: "add_comment", CardID: "c1", Text: "done"},
+	}, &k)
+
+	if got := q.items[0].label; got != `move "Login" → Done` {
+		t.Errorf("label = %q", got)
+	}
+	q.Mark(reviewApproved)
+	q.Mark(reviewRejected)
+	// the third is left pending, which counts as rejected
+	if q.Current() != &q.items[2] {
+		t.Fatalf("cursor = %d, want 2", q.cursor)
+	}
+
+	approved, dropped := q.Take()
+	if len(approved) != 1 || approved[0].Type != "move_card" || dropped != 2 {
+		t.Fatalf("approved = %+v, dropped = %d", approved, dropped)
+	}
+	if q.Active() {
+		t.Fatal("queue still active after Take")
+	}
+}
+
+func TestReviewQueueMarkAll(t *testing.T) {
+	k := reviewBoard()
+	var q reviewQueue
+	q.Add([]agentAction{{Type: "archive_card", CardID: "c1"}, {Type: "undo"}}, &k)
+
+	q.MarkAll(reviewApproved)
+	if approved, dropped := q.Take(); len(approved) != 2 || dropped != 0 {
+		t.Fatalf("approved = %+v, dropped = %d", approved, dropped)
+	}
+
+	q.Add([]agentAction{{Type: "archive_card", CardID: "c1"}, {Type: "undo"}}, &k)
+	q.MarkAll(reviewRejected)
+	if approved, dropped := q.Take(); len(approved) != 0 || dropped != 2 {
+		t.Fatalf("approved = %+v, dropped = %d", approved, dropped)
+	}
+}
+
+func TestReviewQueueEdit(t *testing.T) {
+	m := Model{kanban: reviewBoard()}
+	m.drawer.review.Add([]agentAction{{Type: "create_card", ListID: "l1", Name: "Lgoin"}}, &m.kanban)
+
+	m.editAction(func(a *agentAction) { *a.editText() = "Login page" })
+
+	item := m.drawer.review.Current()
+	if item.state != reviewApproved {
+		t.Errorf("edited action state = %v, want approved", item.state)
+	}
+	if item.label != `create card "Login page" in Todo` {
+		t.Errorf("label = %q", item.label)
+	}
+	approved, _ := m.drawer.review.Take()
+	if len(approved) != 1 || approved[0].Name != "Login page" {
+		t.Fatalf("approved = %+v", approved)
+	}
+}
+
+func TestEditTextTargets(t *testing.T) {
+	rename := agentAction{Type: "update_card", Name: "a", Desc: "b"}
+	if p := rename.editText(); p != &rename.Name {
+		t.Error("update_card with a name should edit the name")
+	}
+	desc := agentAction{Type: "update_card", Desc: "b"}
+	if p := desc.editText(); p != &desc.Desc {
+		t.Error("update_card without a name should edit the description")
+	}
+	for _, typ := range []string{"move_card", "archive_card", "archive_list", "undo"} {
+		a := agentAction{Type: typ}
+		if a.editText() != nil {
+			t.Errorf("%s has editable text", typ)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// maxSearchResults bounds the ranked hits kept per keystroke.
+const maxSearchResults = 50
+
+// searchDelay is how long typing has to pause before a search of every board
+// goes to the provider.
+const searchDelay = 300 * time.Millisecond
+
+// searchOverlay finds a card anywhere on the board by fuzzy matching, ranked
+// as the query is typed. providers that can search every board are asked
+// instead once the scope is switched with tab.
+type searchOverlay struct {
+	visible bool
+	input   textinput.Model
+	results []searchHit
+	cursor  int
+
+	global    bool
+	canGlobal bool
+	// bumped by every query sent to the provider, so replies to older ones
+	// are dropped
+	seq       int
+	searching bool
+	err       string
+}
+
+type searchHit struct {
+	card  board.Card
+	field string // what matched best: name, label, id or desc
+	score int
+	// set for results from every board: the card's board, or the hit itself
+	// when card has no id
+	board *board.Board
+}
+
+func newSearchOverlay() searchOverlay {
+	ti := textinput.New()
+	ti.Prompt = "/ "
+	ti.Placeholder = "name, description, label or id..."
+	ti.CharLimit = 200
+	return searchOverlay{input: ti}
+}
+
+func (s *searchOverlay) Open(global, canGlobal bool) tea.Cmd {
+	s.visible = true
+	s.global = global
+	s.canGlobal = canGlobal
+	s.input.SetValue("")
+	s.results = nil
+	s.cursor = 0
+	s.searching = false
+	s.err = ""
+	return s.input.Focus()
+}
+
+func (s *searchOverlay) Close() {
+	s.visible = false
+	s.input.Blur()
+}
+
+func (s *searchOverlay) Move(delta int) {
+	s.cursor = max(0, min(s.cursor+delta, len(s.results)-1))
+}
+
+// Selected is the hit under the cursor, or nil with no results.
+func (s *searchOverlay) Selected() *searchHit {
+	if s.cursor < 0 || s.cursor >= len(s.results) {
+		return nil
+	}
+	return &s.results[s.cursor]
+}
+
+// Update edits the query and re-ranks the board's cards against it, or
+// schedules a search of every board.
+func (s *searchOverlay) Update(msg tea.Msg, k *KanbanModel) tea.Cmd {
+	prev := s.input.Value()
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+	if s.input.Value() != prev {
+		return tea.Batch(cmd, s.requery(k))
+	}
+	return cmd
+}
+
+// ToggleScope switches between this board and every board.
+func (s *searchOverlay) ToggleScope(k *KanbanModel) tea.Cmd {
+	if !s.canGlobal {
+		return nil
+	}
+	s.global = !s.global
+	s.results = nil
+	return s.requery(k)
+}
+
+func (s *searchOverlay) requery(k *KanbanModel) tea.Cmd {
+	s.cursor = 0
+	s.err = ""
+	if !s.global {
+		s.results = searchCards(k, s.input.Value())
+		return nil
+	}
+	s.seq++
+	s.searching = strings.TrimSpace(s.input.Value()) != ""
+	if !s.searching {
+		s.results = nil
+		return nil
+	}
+	seq := s.seq
+	return tea.Tick(searchDelay, func(time.Time) tea.Msg { return searchDueMsg{seq: seq} })
+}
+
+// SetFound shows what a search of every board returned: boards first, then
+// cards, in the provider's order.
+func (s *searchOverlay) SetFound(found *board.SearchResults, err error) {
+	s.searching = false
+	s.cursor = 0
+	s.results = nil
+	if err != nil {
+		s.err = err.Error()
+		return
+	}
+	s.err = ""
+	for _, b := range found.Boards {
+		s.results = append(s.results, searchHit{board: &b})
+	}
+	for _, c := range found.Cards {
+		b := board.Board{ID: c.BoardID, Name: c.BoardName}
+		s.results = append(s.results, searchHit{card: c.Card, field: "name", board: &b})
+	}
+}
+
+// searchCards ranks every card on the board against query, best first. each
+// space-separated term has to match somewhere on the card; ties keep board
+// order.
+func searchCards(k *KanbanModel, query string) []searchHit {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	var hits []searchHit
+	for _, l := range k.lists {
+		for _, c := range k.cards[l.ID] {
+			if hit, ok := matchCard(c, terms); ok {
+				hits = append(hits, hit)
+			}
+		}
+	}
+	slices.SortStableFunc(hits, func(a, b searchHit) int { return b.score - a.score })
+	if len(hits) > maxSearchResults {
+		hits = hits[:maxSearchResults]
+	}
+	return hits
+}
+
+func matchCard(c board.Card, terms []string) (searchHit, bool) {
+	hit := searchHit{card: c}
+	bestField := 0
+	for _, term := range terms {
+		best, field := 0, ""
+		try := func(name string, score int, ok bool) {
+			if ok && score > best {
+				best, field = score, name
+			}
+		}
+		score, ok := fuzzyScore(term, c.Name)
+		try("name", score+10, ok)
+		for _, lb := range c.Labels {
+			score, ok := fuzzyScore(term, lb.Name)
+			try("label", score+5, ok)
+		}
+		if strings.HasPrefix(strings.ToLower(c.ID), term) {
+			try("id", 20+len(term), true)
+		}
+		// almost any short term is a subsequence of a long description, so
+		// it only counts when the term appears as typed
+		if strings.Contains(strings.ToLower(c.Desc), term) {
+			try("desc", len(term), true)
+		}
+		if best == 0 {
+			return searchHit{}, false
+		}
+		hit.score += best
+		if best > bestField {
+			bestField, hit.field = best, field
+		}
+	}
+	return hit, true
+}
+
+// fuzzyScore matches pattern, already lowercase, as a subsequence of text.
+// consecutive runs and matches at the start of words score higher and gaps
+// cost, so "lgn" ranks "Login page" above "Legacy migration".
+func fuzzyScore(pattern, text string) (int, bool) {
+	if pattern == "" || text == "" {
+		return 0, false
+	}
+	p := []rune(pattern)
+	t := []rune(strings.ToLower(text))
+	score, pi, first, last := 0, 0, -1, -2
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if t[ti] != p[pi] {
+			continue
+		}
+		score++
+		if ti == last+1 {
+			score += 3
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 2
+		}
+		if first < 0 {
+			first = ti
+		}
+		last = ti
+		pi++
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+	score -= (last - first + 1 - len(p)) / 3
+	if strings.Contains(string(t), pattern) {
+		score += 5
+	}
+	return max(1, score), true
+}
+
+// Render draws the query and ranked results over the board.
+func (s *searchOverlay) Render(k *KanbanModel, w, ht int) string {
+	width := max(50, w-16)
+	s.input.Width = max(20, width-8)
+	title := "Search cards"
+	if s.global {
+		title = "Search all boards"
+	}
+	lines := []string{title, "", s.input.View(), ""}
+
+	rows := max(1, ht-14)
+	switch {
+	case s.err != "":
+		lines = append(lines, errorStyle.Render(s.err))
+	case s.searching && len(s.results) == 0:
+		lines = append(lines, subtleStyle.Render("searching..."))
+	case strings.TrimSpace(s.input.Value()) == "" && s.global:
+		lines = append(lines, subtleStyle.Render("type to search every board, e.g. label:bug @me due:week"))
+	case strings.TrimSpace(s.input.Value()) == "":
+		lines = append(lines, subtleStyle.Render("type to search every list on the board"))
+	case len(s.results) == 0:
+		lines = append(lines, subtleStyle.Render("no matches"))
+	}
+	from := max(0, min(s.cursor-rows/2, len(s.results)-rows))
+	for i := from; i < len(s.results) && i < from+rows; i++ {
+		hit := s.results[i]
+		label, where := hit.card.Name, ""
+		switch {
+		case hit.board != nil && hit.card.ID == "":
+			label = hit.board.Name
+			where = "  board · " + shortID(hit.board.ID)
+		case hit.board != nil:
+			where = fmt.Sprintf("  %s › %s · %s", hit.board.Name, hit.card.ListName, shortID(hit.card.ID))
+		default:
+			listName := hit.card.ListName
+			if l := k.findList(hit.card.IDList); l != nil {
+				listName = l.Name
+			}
+			where = fmt.Sprintf("  %s · %s", listName, shortID(hit.card.ID))
+			if hit.field != "name" {
+				where += " · " + hit.field
+			}
+		}
+		name := ellipsis(label, max(10, width-6-lipgloss.Width(where)))
+		if i == s.cursor {
+			lines = append(lines, selectedRowStyle.Render("> "+name)+subtleStyle.Render(where))
+		} else {
+			lines = append(lines, "  "+name+subtleStyle.Render(where))
+		}
+	}
+	if len(s.results) > 0 {
+		lines = append(lines, "", subtleStyle.Render(fmt.Sprintf("%d of %d", s.cursor+1, len(s.results))))
+	}
+	keys := "↑/↓: select  enter: jump to card  Esc: close"
+	switch {
+	case s.canGlobal && s.global:
+		keys = "↑/↓: select  enter: open  tab: this board  Esc: close"
+	case s.canGlobal:
+		keys += "  tab: all boards"
+	}
+	lines = append(lines, "", keys)
+
+	panel := helpPanelStyle.Width(width).Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(w, ht, lipgloss.Center, lipgloss.Center, panel,
+		lipgloss.WithWhitespaceChars("░"),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("238")),
+	)
+}
+
+// jumpTo selects the card with id, scrolling its list into view. a filter
+// that hides the card is dropped.
+func (k *KanbanModel) jumpTo(cardID string) bool {
+	for li, l := range k.lists {
+		i := cardIndex(k.cards[l.ID], cardID)
+		if i < 0 {
+			continue
+		}
+		if !k.shown(k.cards[l.ID][i]) {
+			k.filter = cardFilter{}
+		}
+		k.listCursor = li
+		k.cardCursors[l.ID] = i
+		k.ensureHorizontalScroll()
+		return true
+	}
+	return false
+}
@@ -60,6 +60,21 @@ var (
 				Foreground(lipgloss.Color("186")).
 				Bold(true)
 
+	changedCardStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("215"))
+
+	overdueStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Bold(true)
+	dueSoonStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("221"))
+	dueDoneStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("71"))
+
+	// card history from the provider, as opposed to the local timeline
+	activityStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("109"))
+
+	memberStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("146"))
+
+	// the board filter, shown in the header while active
+	filterStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("179"))
+
 	promptBarStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("67")).
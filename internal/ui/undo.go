@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// maxUndo caps the history; the oldest entries fall off.
+const maxUndo = 50
+
+// undoEntry is a mutation that went through, with the prior state needed to
+// reverse it. comments are only recorded for providers that can delete them.
+type undoEntry struct {
+	action      string // move, rename, update, archive, create, create list, archive list, label, unlabel, assign, unassign, due, due complete, check item, add item, convert item, comment
+	label       string
+	stamp       string
+	cardID      string
+	listID      string           // move: the list the card came from
+	fields      board.CardUpdate // rename/update: the values before the edit
+	labelID     string           // label/unlabel: the label that was added or removed
+	memberID    string           // assign/unassign: the member that was added or removed
+	due         *time.Time       // due: the due date before, nil if there was none
+	dueComplete bool             // due complete: the state to go back to
+	checklistID string           // add item/convert item: the checklist the item was on
+	item        board.CheckItem  // check item: the item as it was; add item/convert item: the item
+	convertedID string           // convert item: the card the item became
+	commentID   string           // comment: the comment that was added
+}
+
+func newUndo(action, label string) *undoEntry {
+	return &undoEntry{action: action, label: label, stamp: time.Now().Format("15:04:05")}
+}
+
+type undoStack struct {
+	entries []undoEntry
+	visible bool
+}
+
+func (s *undoStack) Push(e undoEntry) {
+	s.entries = append(s.entries, e)
+	if len(s.entries) > maxUndo {
+		s.entries = s.entries[len(s.entries)-maxUndo:]
+	}
+}
+
+func (s *undoStack) Pop() (undoEntry, bool) {
+	if len(s.entries) == 0 {
+		return undoEntry{}, false
+	}
+	e := s.entries[len(s.entries)-1]
+	s.entries = s.entries[:len(s.entries)-1]
+	return e, true
+}
+
+// Peek returns the entry the next undo reverts.
+func (s *undoStack) Peek() *undoEntry {
+	if len(s.entries) == 0 {
+		return nil
+	}
+	return &s.entries[len(s.entries)-1]
+}
+
+// Render draws the history panel over the board, newest first.
+func (s *undoStack) Render(w, ht int) string {
+	lines := []string{"Undo history", ""}
+	if len(s.entries) == 0 {
+		lines = append(lines, subtleStyle.Render("nothing to undo"))
+	}
+	rows := max(1, ht-12)
+	for i := len(s.entries) - 1; i >= 0 && len(lines)-2 < rows; i-- {
+		e := s.entries[i]
+		row := fmt.Sprintf("[%s] %s", e.stamp, e.label)
+		if i == len(s.entries)-1 {
+			row = contextMarkerStyle.Render("▸ ") + row
+		} else {
+			row = "  " + subtleStyle.Render(row)
+		}
+		lines = append(lines, row)
+	}
+	lines = append(lines, "", "u: undo latest  U or Esc: close")
+
+	panel := helpPanelStyle.Width(max(50, w-16)).Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(w, ht, lipgloss.Center, lipgloss.Center, panel,
+		lipgloss.WithWhitespaceChars("░"),
+		lipgloss.WithWhitespaceForeground(lipgloss.Color("238")),
+	)
+}
@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/codywilliamson/aboard/internal/board"
+)
+
+// recordingProvider logs each call as "Method arg,arg" and succeeds.
+type recordingProvider struct {
+	calls []string
+}
+
+func (p *recordingProvider) record(method string, args ...string) {
+	p.calls = append(p.calls, method+" "+strings.Join(args, ","))
+}
+
+func (p *recordingProvider) Name() string  { return "fake" }
+func (p *recordingProvider) CanAuth() bool { return true }
+func (p *recordingProvider) Boards(ctx context.Context) ([]board.Board, error) {
+	return nil, nil
+}
+func (p *recordingProvider) Lists(ctx context.Context, boardID string) ([]board.List, error) {
+	return nil, nil
+}
+func (p *recordingProvider) Cards(ctx context.Context, boardID string) ([]board.Card, error) {
+	return nil, nil
+}
+func (p *recordingProvider) MoveCard(ctx context.Context, cardID, listID string) error {
+	p.record("MoveCard", cardID, listID)
+	return nil
+}
+func (p *recordingProvider) UpdateCard(ctx context.Context, cardID string, fields board.CardUpdate) error {
+	args := []string{cardID}
+	if fields.Name != nil {
+		args = append(args, "name="+*fields.Name)
+	}
+	if fields.Desc != nil {
+		args = append(args, "desc="+*fields.Desc)
+	}
+	p.record("UpdateCard", args...)
+	return nil
+}
+func (p *recordingProvider) AddComment(ctx context.Context, cardID, text string) error {
+	p.record("AddComment", cardID, text)
+	return nil
+}
+func (p *recordingProvider) ArchiveCard(ctx context.Context, cardID string) error {
+	p.record("ArchiveCard", cardID)
+	return nil
+}
+func (p *recordingProvider) CreateCard(ctx context.Context, listID, name string) (*board.Card, error) {
+	p.record("CreateCard", listID, name)
+	return &board.Card{ID: "new", Name: name, IDList: listID}, nil
+}
+func (p *recordingProvider) CreateList(ctx context.Context, boardID, name string) (*board.List, error) {
+	p.record("CreateList", boardID, name)
+	return &board.List{ID: "newlist", Name: name}, nil
+}
+func (p *recordingProvider) ArchiveList(ctx context.Context, listID string) error {
+	p.record("ArchiveList", listID)
+	return nil
+}
+func (p *recordingProvider) RestoreCard(ctx context.Context, cardID string) error {
+	p.record("RestoreCard", cardID)
+	return nil
+}
+func (p *recordingProvider) RestoreList(ctx context.Context, listID string) error {
+	p.record("RestoreList", listID)
+	return nil
+}
+
+// commentingProvider can also delete comments.
+type commentingProvider struct {
+	recordingProvider
+}
+
+func (p *commentingProvider) PostComment(ctx context.Context, cardID, text string) (string, error) {
+	p.record("PostComment", cardID, text)
+	return "comment1", nil
+}
+func (p *commentingProvider) DeleteComment(ctx context.Context, cardID, commentID string) error {
+	p.record("DeleteComment", cardID, commentID)
+	return nil
+}
+
+func TestUndoStackCap(t *testing.T) {
+	var s undoStack
+	for i := range maxUndo + 5 {
+		s.Push(undoEntry{label: fmt.Sprint(i)})
+	}
+	if len(s.entries) != maxUndo {
+		t.Fatalf("len = %d, want %d", len(s.entries), maxUndo)
+	}
+	if s.entries[0].label != "5" {
+		t.Errorf("oldest = %s, want 5", s.entries[0].label)
+	}
+	if top := s.Peek(); top == nil || top.label != fmt.Sprint(maxUndo+4) {
+		t.Fatalf("top = %+v", top)
+	}
+	for range maxUndo {
+		if _, ok := s.Pop(); !ok {
+			t.Fatal("ran out early")
+		}
+	}
+	if _, ok := s.Pop(); ok || s.Peek() != nil {
+		t.Fatal("empty stack still pops")
+	}
+}
+
+// mutateAndUndo runs a mutation, then the undo it recorded, returning the
+// provider calls the undo made.
+func mutateAndUndo(t *testing.T, p *recordingProvider, mutation func(board.BoardProvider) tea.Cmd) []string {
+	t.Helper()
+	return mutateAndUndoWith(t, p, &p.calls, mutation)
+}
+
+func mutateAndUndoWith(t *testing.T, client board.BoardProvider, calls *[]string, mutation func(board.BoardProvider) tea.Cmd) []string {
+	t.Helper()
+	var e *undoEntry
+	switch msg := mutation(client)().(type) {
+	case cardMutatedMsg:
+		if msg.err != nil {
+			t.Fatal(msg.err)
+		}
+		e = msg.undo
+	case listMutatedMsg:
+		if msg.err != nil {
+			t.Fatal(msg.err)
+		}
+		e = msg.undo
+	default:
+		t.Fatalf("unexpected %T", msg)
+	}
+	if e == nil {
+		t.Fatal("no undo entry recorded")
+	}
+	before := len(*calls)
+	done := undoCmd(client, *e)().(undoneMsg)
+	if done.err != nil {
+		t.Fatal(done.err)
+	}
+	return (*calls)[before:]
+}
+
+func TestUndoInverses(t *testing.T) {
+	card := &board.Card{ID: "c1", Name: "Login", Desc: "old", IDList: "l1", ListName: "Todo"}
+	list := &board.List{ID: "l2", Name: "Later"}
+	name, desc := "Sign in", "new"
+
+	tests := []struct {
+		name     string
+		mutation func(board.BoardProvider) tea.Cmd
+		want     string
+	}{
+		{"move", func(c board.BoardProvider) tea.Cmd { return moveCardCmd(c, "c1", "l2", card) }, "MoveCard c1,l1"},
+		{"rename", func(c board.BoardProvider) tea.Cmd {
+			return updateCardCmd(c, "c1", board.CardUpdate{Name: &name}, card)
+		}, "UpdateCard c1,name=Login"},
+		{"update", func(c board.BoardProvider) tea.Cmd {
+			return updateCardCmd(c, "c1", board.CardUpdate{Desc: &desc}, card)
+		}, "UpdateCard c1,desc=old"},
+		{"archive", func(c board.BoardProvider) tea.Cmd { return archiveCardCmd(c, "c1", card) }, "RestoreCard c1"},
+		{"create", func(c board.BoardProvider) tea.Cmd { return createCardCmd(c, "l1", "Draft") }, "ArchiveCard new"},
+		{"create list", func(c board.BoardProvider) tea.Cmd { return createListCmd(c, "b1", "Later") }, "ArchiveList newlist"},
+		{"archive list", func(c board.BoardProvider) tea.Cmd { return archiveListCmd(c, "l2", list) }, "RestoreList l2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &recordingProvider{}
+			calls := mutateAndUndo(t, p, tt.mutation)
+			if len(calls) != 1 || calls[0] != tt.want {
+				t.Fatalf("undo made %v, want [%s]", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestUndoComment(t *testing.T) {
+	card := &board.Card{ID: "c1", Name: "Login"}
+
+	p := &commentingProvider{}
+	calls := mutateAndUndoWith(t, p, &p.calls, func(c board.BoardProvider) tea.Cmd {
+		return addCommentCmd(c, "c1", "looks good", card)
+	})
+	if len(calls) != 1 || calls[0] != "DeleteComment c1,comment1" {
+		t.Fatalf("undo made %v", calls)
+	}
+
+	// providers that can't delete comments don't record them
+	plain := &recordingProvider{}
+	msg := addCommentCmd(plain, "c1", "looks good", card)().(cardMutatedMsg)
+	if msg.err != nil || msg.undo != nil {
+		t.Fatalf("msg = %+v", msg)
+	}
+	if len(plain.calls) != 1 || plain.calls[0] != "AddComment c1,looks good" {
+		t.Fatalf("calls = %v", plain.calls)
+	}
+}
+
+func TestUpdateCardWithoutFields(t *testing.T) {
+	p := &recordingProvider{}
+	msg := updateCardCmd(p, "c1", board.CardUpdate{}, &board.Card{ID: "c1", Name: "Login"})().(cardMutatedMsg)
+	if msg.err != nil || msg.undo != nil || len(p.calls) != 0 {
+		t.Fatalf("msg = %+v, calls = %v", msg, p.calls)
+	}
+}